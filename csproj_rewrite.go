@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// versionAttrPattern matches a Version="..." attribute within a single
+// element's raw bytes, capturing everything up to the opening quote so the
+// original spacing around "=" survives a rewrite.
+var versionAttrPattern = regexp.MustCompile(`(Version\s*=\s*")[^"]*"`) //nolint:gochecknoglobals
+
+// rewriteElementVersions rewrites the Version attribute of every elementName
+// element (PackageReference in a .csproj, PackageVersion in a
+// Directory.Packages.props) whose Include/Update attribute is a key in
+// updates. It does not round-trip through encoding/xml's marshaller, which
+// reorders attributes and drops comments and whitespace; instead it tokenizes
+// data to find each matching element's exact byte range and patches only the
+// Version attribute value within that range, leaving every other byte as-is.
+func rewriteElementVersions(data []byte, elementName string, updates map[string]string) ([]byte, error) {
+	if len(updates) == 0 {
+		return data, nil
+	}
+
+	type edit struct {
+		start, end int64
+		newBytes   []byte
+	}
+
+	var edits []edit
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var offset int64
+	for {
+		start := offset
+		tok, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			return nil, fmt.Errorf("error tokenizing xml: %w", err)
+		}
+		offset = decoder.InputOffset()
+
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != elementName {
+			continue
+		}
+
+		name := attrValue(se, "Include")
+		if name == "" {
+			name = attrValue(se, "Update")
+		}
+
+		newVersion, ok := updates[name]
+		if !ok {
+			continue
+		}
+
+		raw := data[start:offset]
+		if !versionAttrPattern.Match(raw) {
+			continue
+		}
+
+		replacement := versionAttrPattern.ReplaceAll(raw, []byte(fmt.Sprintf(`${1}%s"`, newVersion)))
+		edits = append(edits, edit{start: start, end: offset, newBytes: replacement})
+	}
+
+	var out bytes.Buffer
+
+	var cursor int64
+	for _, e := range edits {
+		out.Write(data[cursor:e.start])
+		out.Write(e.newBytes)
+		cursor = e.end
+	}
+	out.Write(data[cursor:])
+
+	return out.Bytes(), nil
+}
+
+// attrValue returns the value of the named attribute on a start element, or
+// "" if it isn't present.
+func attrValue(se xml.StartElement, name string) string {
+	for _, attr := range se.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRewriteElementVersions(t *testing.T) {
+	data := []byte(`<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <!-- keep this comment -->
+    <PackageReference Include="Newtonsoft.Json" Version="12.0.1" />
+    <PackageReference Include="Serilog"
+                       Version="2.10.0" />
+    <PackageReference Include="Unrelated.Pkg" Version="1.0.0" />
+  </ItemGroup>
+</Project>
+`)
+
+	out, err := rewriteElementVersions(data, "PackageReference", map[string]string{
+		"Newtonsoft.Json": "13.0.3",
+		"Serilog":         "2.12.0",
+	})
+	if err != nil {
+		t.Fatalf("rewriteElementVersions: %v", err)
+	}
+
+	got := string(out)
+
+	if !strings.Contains(got, `Include="Newtonsoft.Json" Version="13.0.3"`) {
+		t.Errorf("Newtonsoft.Json version not updated, got:\n%s", got)
+	}
+
+	if !strings.Contains(got, "Serilog\"\n                       Version=\"2.12.0\"") {
+		t.Errorf("Serilog's attribute-on-its-own-line formatting was not preserved, got:\n%s", got)
+	}
+
+	if !strings.Contains(got, `Version="1.0.0"`) {
+		t.Errorf("unrelated package was modified, got:\n%s", got)
+	}
+
+	if !strings.Contains(got, "<!-- keep this comment -->") {
+		t.Errorf("comment was dropped, got:\n%s", got)
+	}
+}
+
+func TestRewriteElementVersionsUpdateAttribute(t *testing.T) {
+	data := []byte(`<Project>
+  <ItemGroup>
+    <PackageReference Update="Foo.Bar" Version="1.0.0" />
+  </ItemGroup>
+</Project>
+`)
+
+	out, err := rewriteElementVersions(data, "PackageReference", map[string]string{"Foo.Bar": "1.1.0"})
+	if err != nil {
+		t.Fatalf("rewriteElementVersions: %v", err)
+	}
+
+	if !strings.Contains(string(out), `Update="Foo.Bar" Version="1.1.0"`) {
+		t.Errorf("Update-keyed reference not updated, got:\n%s", out)
+	}
+}
+
+func TestRewriteElementVersionsNoUpdatesIsNoop(t *testing.T) {
+	data := []byte(`<PackageReference Include="Foo" Version="1.0.0" />`)
+
+	out, err := rewriteElementVersions(data, "PackageReference", nil)
+	if err != nil {
+		t.Fatalf("rewriteElementVersions: %v", err)
+	}
+
+	if string(out) != string(data) {
+		t.Errorf("expected unchanged bytes with no updates, got:\n%s", out)
+	}
+}
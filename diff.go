@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffOpKind identifies one line of a line-based diff.
+type diffOpKind int
+
+const (
+	diffEqual diffOpKind = iota
+	diffDelete
+	diffInsert
+)
+
+type diffOp struct {
+	kind diffOpKind
+	line string
+}
+
+// diffContextLines is how many unchanged lines of context surround each
+// changed run, matching `diff -u`'s default. A 50+ package .csproj with one
+// version bumped would otherwise dump the entire file back at the user,
+// defeating the point of reviewing a diff before --fix.
+const diffContextLines = 3
+
+// unifiedDiff returns a unified diff between before and after for --dry-run
+// output, labelled with path. It returns "" when the two are identical.
+func unifiedDiff(path string, before, after []byte) string {
+	a := strings.Split(string(before), "\n")
+	b := strings.Split(string(after), "\n")
+
+	ops := diffLines(a, b)
+	if !diffHasChanges(ops) {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n", path)
+	fmt.Fprintf(&sb, "+++ b/%s\n", path)
+
+	for _, hunk := range diffHunks(ops, diffContextLines) {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunk.aRange(), hunk.bRange())
+
+		for _, op := range hunk.ops {
+			switch op.kind {
+			case diffEqual:
+				fmt.Fprintf(&sb, " %s\n", op.line)
+			case diffDelete:
+				fmt.Fprintf(&sb, "-%s\n", op.line)
+			case diffInsert:
+				fmt.Fprintf(&sb, "+%s\n", op.line)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// diffHunk is one contiguous run of changed ops padded with up to
+// diffContextLines of surrounding equal lines, plus the line ranges it
+// covers in a and b for its "@@" header.
+type diffHunk struct {
+	aStart, aCount int
+	bStart, bCount int
+	ops            []diffOp
+}
+
+// aRange formats h's "a" side the way `diff -u` does: "start,count", except a
+// zero-length count (a pure insertion) reports the line after which it
+// applies instead of a 1-based start that wouldn't exist.
+func (h diffHunk) aRange() string {
+	return hunkRange(h.aStart, h.aCount)
+}
+
+// bRange is aRange for h's "b" side.
+func (h diffHunk) bRange() string {
+	return hunkRange(h.bStart, h.bCount)
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// diffHunks groups ops into hunks around each changed run, padding each side
+// with up to context equal lines and merging runs whose padding overlaps.
+func diffHunks(ops []diffOp, context int) []diffHunk {
+	aPos, bPos := diffLinePositions(ops)
+
+	var ranges [][2]int // [start, end) into ops, inclusive of context padding
+
+	for i, op := range ops {
+		if op.kind == diffEqual {
+			continue
+		}
+
+		start, end := i-context, i+1+context
+		if start < 0 {
+			start = 0
+		}
+
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		if n := len(ranges); n > 0 && start <= ranges[n-1][1] {
+			if end > ranges[n-1][1] {
+				ranges[n-1][1] = end
+			}
+		} else {
+			ranges = append(ranges, [2]int{start, end})
+		}
+	}
+
+	hunks := make([]diffHunk, 0, len(ranges))
+
+	for _, r := range ranges {
+		start, end := r[0], r[1]
+		hunks = append(hunks, diffHunk{
+			aStart: aPos[start],
+			aCount: aPos[end] - aPos[start],
+			bStart: bPos[start],
+			bCount: bPos[end] - bPos[start],
+			ops:    ops[start:end],
+		})
+	}
+
+	return hunks
+}
+
+// diffLinePositions returns, for each index into ops, how many a-side and
+// b-side lines precede it: aPos[i]/bPos[i] is the 0-based line number ops[i]
+// starts at in a/b.
+func diffLinePositions(ops []diffOp) (aPos, bPos []int) {
+	aPos = make([]int, len(ops)+1)
+	bPos = make([]int, len(ops)+1)
+
+	for i, op := range ops {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+
+		switch op.kind {
+		case diffEqual:
+			aPos[i+1]++
+			bPos[i+1]++
+		case diffDelete:
+			aPos[i+1]++
+		case diffInsert:
+			bPos[i+1]++
+		}
+	}
+
+	return aPos, bPos
+}
+
+// diffLines computes a line-based diff between a and b via a longest-common-
+// subsequence table. csproj and lock files are small enough that the O(n*m)
+// table is cheap, and it keeps the diff minimal around the handful of Version
+// attributes an update actually touches.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{kind: diffEqual, line: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{kind: diffDelete, line: a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: diffInsert, line: b[j]})
+			j++
+		}
+	}
+
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{kind: diffDelete, line: a[i]})
+	}
+
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{kind: diffInsert, line: b[j]})
+	}
+
+	return ops
+}
+
+func diffHasChanges(ops []diffOp) bool {
+	for _, op := range ops {
+		if op.kind != diffEqual {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiffIdenticalReturnsEmpty(t *testing.T) {
+	content := []byte("line1\nline2\nline3\n")
+
+	if got := unifiedDiff("a.csproj", content, content); got != "" {
+		t.Errorf("unifiedDiff(identical) = %q, want \"\"", got)
+	}
+}
+
+func TestUnifiedDiffPureInsert(t *testing.T) {
+	before := []byte("a\nb\n")
+	after := []byte("a\nx\nb\n")
+
+	got := unifiedDiff("a.csproj", before, after)
+
+	want := "--- a/a.csproj\n" +
+		"+++ b/a.csproj\n" +
+		"@@ -1,3 +1,4 @@\n" +
+		" a\n" +
+		"+x\n" +
+		" b\n" +
+		" \n"
+
+	if got != want {
+		t.Errorf("unifiedDiff(insert) =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnifiedDiffPureDelete(t *testing.T) {
+	before := []byte("a\nx\nb\n")
+	after := []byte("a\nb\n")
+
+	got := unifiedDiff("a.csproj", before, after)
+
+	want := "--- a/a.csproj\n" +
+		"+++ b/a.csproj\n" +
+		"@@ -1,4 +1,3 @@\n" +
+		" a\n" +
+		"-x\n" +
+		" b\n" +
+		" \n"
+
+	if got != want {
+		t.Errorf("unifiedDiff(delete) =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnifiedDiffTrimsContextAroundAChangeFarFromOtherChanges(t *testing.T) {
+	// 20 unchanged lines, a single changed line in the middle, 20 more
+	// unchanged lines: only diffContextLines of padding on each side should
+	// appear, not the whole file.
+	var before, after []string
+	for i := 1; i <= 20; i++ {
+		before = append(before, fmt.Sprintf("line%d", i))
+	}
+
+	after = append(after, before...)
+	before = append(before, "old", "tail")
+	after = append(after, "new", "tail")
+
+	for i := 21; i <= 40; i++ {
+		before = append(before, fmt.Sprintf("line%d", i))
+		after = append(after, fmt.Sprintf("line%d", i))
+	}
+
+	got := unifiedDiff("big.csproj", []byte(strings.Join(before, "\n")), []byte(strings.Join(after, "\n")))
+
+	if strings.Count(got, "@@") != 2 {
+		t.Fatalf("unifiedDiff produced %d hunk headers, want 1:\n%s", strings.Count(got, "@@")/2, got)
+	}
+
+	if strings.Contains(got, "line1\n") || strings.Contains(got, "line40\n") {
+		t.Errorf("unifiedDiff included lines outside the context window, got:\n%s", got)
+	}
+
+	for _, want := range []string{"line18", "line19", "line20", "-old", "+new", "tail", "line21", "line22"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("unifiedDiff missing expected context/change line %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestUnifiedDiffMergesHunksWhoseContextOverlaps(t *testing.T) {
+	before := []string{"1", "2", "old-a", "4", "5", "old-b", "7", "8"}
+	after := []string{"1", "2", "new-a", "4", "5", "new-b", "7", "8"}
+
+	got := unifiedDiff("a.csproj", []byte(strings.Join(before, "\n")), []byte(strings.Join(after, "\n")))
+
+	if strings.Count(got, "@@") != 2 {
+		t.Fatalf("unifiedDiff produced %d hunk headers, want the nearby changes merged into 1:\n%s", strings.Count(got, "@@")/2, got)
+	}
+}
+
+func TestUnifiedDiffLabelsBothSidesWithPath(t *testing.T) {
+	got := unifiedDiff("src/a.csproj", []byte("a\n"), []byte("b\n"))
+
+	if !strings.HasPrefix(got, "--- a/src/a.csproj\n+++ b/src/a.csproj\n") {
+		t.Errorf("unifiedDiff did not label both sides with path, got:\n%s", got)
+	}
+}
+
+func TestDiffLinesEqualInputsProduceOnlyEqualOps(t *testing.T) {
+	lines := []string{"a", "b", "c"}
+
+	ops := diffLines(lines, lines)
+
+	if len(ops) != len(lines) {
+		t.Fatalf("diffLines(equal) produced %d ops, want %d", len(ops), len(lines))
+	}
+
+	for i, op := range ops {
+		if op.kind != diffEqual || op.line != lines[i] {
+			t.Errorf("ops[%d] = %+v, want equal op for %q", i, op, lines[i])
+		}
+	}
+}
+
+func TestDiffLinesAppendAtEnd(t *testing.T) {
+	a := []string{"a", "b"}
+	b := []string{"a", "b", "c"}
+
+	ops := diffLines(a, b)
+
+	want := []diffOp{
+		{kind: diffEqual, line: "a"},
+		{kind: diffEqual, line: "b"},
+		{kind: diffInsert, line: "c"},
+	}
+
+	if len(ops) != len(want) {
+		t.Fatalf("diffLines(append) = %+v, want %+v", ops, want)
+	}
+
+	for i := range want {
+		if ops[i] != want[i] {
+			t.Errorf("ops[%d] = %+v, want %+v", i, ops[i], want[i])
+		}
+	}
+}
+
+func TestDiffLinesTruncateAtEnd(t *testing.T) {
+	a := []string{"a", "b", "c"}
+	b := []string{"a", "b"}
+
+	ops := diffLines(a, b)
+
+	want := []diffOp{
+		{kind: diffEqual, line: "a"},
+		{kind: diffEqual, line: "b"},
+		{kind: diffDelete, line: "c"},
+	}
+
+	if len(ops) != len(want) {
+		t.Fatalf("diffLines(truncate) = %+v, want %+v", ops, want)
+	}
+
+	for i := range want {
+		if ops[i] != want[i] {
+			t.Errorf("ops[%d] = %+v, want %+v", i, ops[i], want[i])
+		}
+	}
+}
+
+func TestDiffHasChangesFalseForAllEqual(t *testing.T) {
+	ops := []diffOp{
+		{kind: diffEqual, line: "a"},
+		{kind: diffEqual, line: "b"},
+	}
+
+	if diffHasChanges(ops) {
+		t.Errorf("diffHasChanges(all equal) = true, want false")
+	}
+}
+
+func TestDiffHasChangesTrueWhenAnyOpDiffers(t *testing.T) {
+	ops := []diffOp{
+		{kind: diffEqual, line: "a"},
+		{kind: diffInsert, line: "b"},
+	}
+
+	if !diffHasChanges(ops) {
+		t.Errorf("diffHasChanges(with insert) = false, want true")
+	}
+}
@@ -0,0 +1,98 @@
+package nuget
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// cacheEntry is what's persisted on disk for one cached response: the
+// validators needed to revalidate with a conditional GET, and the body to
+// fall back to on a 304.
+type cacheEntry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Body         []byte `json:"body,omitempty"`
+}
+
+// Cache is an on-disk, revalidating response cache for registration index
+// lookups, keyed by package id and rooted under $XDG_CACHE_HOME/nugbot (or
+// the OS default cache directory). It exists so that re-running nugbot
+// against the same .csproj doesn't re-download a registration index that
+// hasn't changed since the last run.
+type Cache struct {
+	dir string
+}
+
+// NewCache returns a Cache rooted under the user's cache directory. A Cache
+// whose directory can't be determined or created is still safe to use: Get
+// always misses and Put is a no-op, so callers don't need a nil check.
+func NewCache() *Cache {
+	dir, err := cacheDir()
+	if err != nil {
+		return &Cache{}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return &Cache{}
+	}
+
+	return &Cache{dir: dir}
+}
+
+// cacheDir resolves $XDG_CACHE_HOME/nugbot, falling back to the OS default
+// user cache directory when XDG_CACHE_HOME isn't set.
+func cacheDir() (string, error) {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "nugbot"), nil
+	}
+
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "nugbot"), nil
+}
+
+// Get returns the cached entry for packageID, if the cache has one.
+func (c *Cache) Get(packageID string) (cacheEntry, bool) {
+	if c.dir == "" {
+		return cacheEntry{}, false
+	}
+
+	data, err := os.ReadFile(c.path(packageID))
+	if err != nil {
+		return cacheEntry{}, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return cacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+// Put stores entry for packageID. A failure to write isn't fatal to the
+// caller; it just costs a cache hit on the next run.
+func (c *Cache) Put(packageID string, entry cacheEntry) {
+	if c.dir == "" {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	_ = os.WriteFile(c.path(packageID), data, 0o644)
+}
+
+// path returns the cache file for packageID.
+func (c *Cache) path(packageID string) string {
+	safe := strings.NewReplacer("/", "_", `\`, "_").Replace(strings.ToLower(packageID))
+
+	return filepath.Join(c.dir, safe+".json")
+}
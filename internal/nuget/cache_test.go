@@ -0,0 +1,49 @@
+package nuget
+
+import (
+	"testing"
+)
+
+func TestCacheGetMissOnEmptyCache(t *testing.T) {
+	c := &Cache{dir: t.TempDir()}
+
+	if _, ok := c.Get("Some.Package"); ok {
+		t.Error("Get on an empty cache should miss")
+	}
+}
+
+func TestCachePutGetRoundTrip(t *testing.T) {
+	c := &Cache{dir: t.TempDir()}
+
+	want := cacheEntry{ETag: `"abc"`, LastModified: "Tue, 01 Jul 2025 00:00:00 GMT", Body: []byte(`{"ok":true}`)}
+	c.Put("Some.Package", want)
+
+	got, ok := c.Get("Some.Package")
+	if !ok {
+		t.Fatal("expected a cache hit after Put")
+	}
+
+	if got.ETag != want.ETag || got.LastModified != want.LastModified || string(got.Body) != string(want.Body) {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCachePathIsCaseInsensitiveAndPathSafe(t *testing.T) {
+	c := &Cache{dir: t.TempDir()}
+
+	c.Put("My/Weird\\Package", cacheEntry{Body: []byte("a")})
+
+	if _, ok := c.Get("MY/WEIRD\\PACKAGE"); !ok {
+		t.Error("Get should be case-insensitive and treat slashes/backslashes the same as Put did")
+	}
+}
+
+func TestNewCacheZeroValueIsSafe(t *testing.T) {
+	c := &Cache{}
+
+	c.Put("Some.Package", cacheEntry{Body: []byte("a")}) // must not panic
+
+	if _, ok := c.Get("Some.Package"); ok {
+		t.Error("a Cache with no directory should always miss")
+	}
+}
@@ -0,0 +1,334 @@
+package nuget
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// Resource @type values nugbot looks up in a feed's service index.
+const (
+	// registrationsResourceType is the gzipped, SemVer2-aware registration
+	// resource used to list a package's known versions.
+	registrationsResourceType = "RegistrationsBaseUrl/3.6.0"
+	// packageBaseAddressResourceType is the flat-container resource used to
+	// fetch a specific version's .nuspec.
+	packageBaseAddressResourceType = "PackageBaseAddress/3.0.0"
+)
+
+// requestTimeout bounds a single HTTP round trip, so a wedged feed can't
+// hang a whole run.
+const requestTimeout = 30 * time.Second
+
+// Retry tuning for 5xx/429 responses: exponential backoff unless the
+// server names its own wait via Retry-After.
+const (
+	maxRetries     = 3
+	retryBaseDelay = 500 * time.Millisecond
+)
+
+// Client queries one or more NuGet v3 feeds for a package's registration
+// index and nuspec, handling per-feed authentication and caching each feed's
+// service index for the process lifetime (it rarely changes, and is one
+// extra round trip per feed otherwise). Registration index lookups are also
+// revalidated against an on-disk Cache across runs.
+type Client struct {
+	HTTPClient *http.Client
+	Config     *Config
+	Cache      *Cache // optional; nil disables on-disk response caching
+
+	mu      sync.Mutex
+	indexes map[string][]byte // feed URL -> raw service index body
+}
+
+// maxIdleConnsPerHost raises Go's default of 2, which would otherwise force
+// most of a concurrent run's requests to a single feed through fresh
+// connections instead of the pool.
+const maxIdleConnsPerHost = 16
+
+// NewClient builds a Client from a discovered Config, with a timeout'd HTTP
+// client sized for concurrent requests and an on-disk response cache under
+// $XDG_CACHE_HOME/nugbot.
+func NewClient(cfg *Config) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+
+	return &Client{
+		HTTPClient: &http.Client{Timeout: requestTimeout, Transport: transport},
+		Config:     cfg,
+		Cache:      NewCache(),
+		indexes:    map[string][]byte{},
+	}
+}
+
+// FetchRegistrationIndex returns the raw registration index JSON for
+// packageID, trying each source mapped to it (via packageSourceMapping, or
+// every configured source if none applies) in order and returning the first
+// one that answers successfully.
+func (c *Client) FetchRegistrationIndex(ctx context.Context, packageID string) ([]byte, error) {
+	sources := c.Config.SourcesForPackage(packageID)
+	if len(sources) == 0 {
+		return nil, c.noSourceError(packageID)
+	}
+
+	var lastErr error
+
+	for _, source := range sources {
+		body, err := c.fetchFromSource(ctx, source, packageID)
+		if err != nil {
+			lastErr = err
+
+			continue
+		}
+
+		return body, nil
+	}
+
+	return nil, lastErr
+}
+
+func (c *Client) fetchFromSource(ctx context.Context, source Source, packageID string) ([]byte, error) {
+	base, err := c.resourceURL(ctx, source, registrationsResourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/%s/index.json", strings.TrimRight(base, "/"), strings.ToLower(packageID))
+
+	return c.getCached(ctx, source, url, packageID)
+}
+
+// FetchNuspec returns the .nuspec contents for packageID at version, trying
+// each source mapped to the package via its flat-container
+// (PackageBaseAddress) resource.
+func (c *Client) FetchNuspec(ctx context.Context, packageID, version string) ([]byte, error) {
+	sources := c.Config.SourcesForPackage(packageID)
+	if len(sources) == 0 {
+		return nil, c.noSourceError(packageID)
+	}
+
+	var lastErr error
+
+	for _, source := range sources {
+		base, err := c.resourceURL(ctx, source, packageBaseAddressResourceType)
+		if err != nil {
+			lastErr = err
+
+			continue
+		}
+
+		id := strings.ToLower(packageID)
+		url := fmt.Sprintf("%s/%s/%s/%s.nuspec", strings.TrimRight(base, "/"), id, strings.ToLower(version), id)
+
+		body, err := c.get(ctx, source, url)
+		if err != nil {
+			lastErr = err
+
+			continue
+		}
+
+		return body, nil
+	}
+
+	return nil, lastErr
+}
+
+// noSourceError reports why packageID has no source to query: packageSourceMapping
+// is configured but none of its patterns matched, or there are no sources at
+// all.
+func (c *Client) noSourceError(packageID string) error {
+	if len(c.Config.Mappings) > 0 {
+		return fmt.Errorf("no source mapped for package %s", packageID) //nolint:goerr113
+	}
+
+	return fmt.Errorf("no package source configured for %s", packageID) //nolint:goerr113
+}
+
+// resourceURL returns the @id of the named resource @type from source's
+// service index, fetching and caching the service index on first use.
+func (c *Client) resourceURL(ctx context.Context, source Source, resourceType string) (string, error) {
+	body, err := c.serviceIndex(ctx, source)
+	if err != nil {
+		return "", err
+	}
+
+	url := findResource(body, resourceType)
+	if url == "" {
+		return "", fmt.Errorf("no %s resource in service index for %s", resourceType, source.Name) //nolint:goerr113
+	}
+
+	return url, nil
+}
+
+// serviceIndex returns source's service index, fetching it at most once even
+// when called concurrently by the worker pool checkForUpdates runs: the lock
+// is held across the fetch itself, not just the cache check, so parallel
+// callers for the same source queue behind the first fetch instead of each
+// firing their own request.
+func (c *Client) serviceIndex(ctx context.Context, source Source) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if body, ok := c.indexes[source.URL]; ok {
+		return body, nil
+	}
+
+	body, err := c.get(ctx, source, source.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	c.indexes[source.URL] = body
+
+	return body, nil
+}
+
+// findResource picks the @id of the resource matching resourceType exactly,
+// falling back to any resource whose @type shares its prefix (feeds often
+// expose several versioned variants of the same resource family).
+func findResource(body []byte, resourceType string) string {
+	prefix, _, _ := strings.Cut(resourceType, "/")
+
+	var fallback string
+
+	for _, res := range gjson.GetBytes(body, "resources").Array() {
+		t := res.Get("@type").String()
+		id := res.Get("@id").String()
+
+		if t == resourceType {
+			return id
+		}
+
+		if fallback == "" && strings.HasPrefix(t, prefix) {
+			fallback = id
+		}
+	}
+
+	return fallback
+}
+
+// get performs a GET against url with retry, but no on-disk caching.
+func (c *Client) get(ctx context.Context, source Source, url string) ([]byte, error) {
+	body, _, _, _, err := c.doGet(ctx, source, url, cacheEntry{})
+
+	return body, err
+}
+
+// getCached performs a GET against url, revalidating against the on-disk
+// cache entry for cacheKey with If-None-Match/If-Modified-Since and storing
+// a fresh entry on a 200 that carries an ETag or Last-Modified (a 200
+// without either is not cached). When c.Cache is nil it behaves exactly like
+// get.
+func (c *Client) getCached(ctx context.Context, source Source, url, cacheKey string) ([]byte, error) {
+	if c.Cache == nil {
+		return c.get(ctx, source, url)
+	}
+
+	cached, _ := c.Cache.Get(cacheKey)
+
+	body, etag, lastModified, notModified, err := c.doGet(ctx, source, url, cached)
+	if err != nil {
+		return nil, err
+	}
+
+	if notModified {
+		return cached.Body, nil
+	}
+
+	if etag != "" || lastModified != "" {
+		c.Cache.Put(cacheKey, cacheEntry{ETag: etag, LastModified: lastModified, Body: body})
+	}
+
+	return body, nil
+}
+
+// doGet performs url, retrying on a network error or a 5xx/429 response
+// with exponential backoff (honoring a Retry-After the server sends). cached
+// supplies the validators for a conditional request; its zero value sends
+// none. notModified reports a 304, in which case body is nil and the caller
+// already holds the cached body.
+func (c *Client) doGet(
+	ctx context.Context, source Source, url string, cached cacheEntry,
+) (body []byte, etag, lastModified string, notModified bool, err error) {
+	for attempt := 0; ; attempt++ {
+		req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if reqErr != nil {
+			return nil, "", "", false, fmt.Errorf("error building request for %s: %w", url, reqErr)
+		}
+
+		if cred, ok := c.Config.Creds[source.Name]; ok {
+			req.SetBasicAuth(cred.Username, cred.Password)
+		}
+
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+
+		resp, doErr := c.HTTPClient.Do(req)
+		if doErr != nil {
+			if attempt >= maxRetries || !sleepBackoff(ctx, attempt, "") {
+				return nil, "", "", false, fmt.Errorf("error fetching %s: %w", url, doErr)
+			}
+
+			continue
+		}
+
+		if resp.StatusCode == http.StatusNotModified {
+			resp.Body.Close()
+
+			return nil, cached.ETag, cached.LastModified, true, nil
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			defer resp.Body.Close()
+
+			data, readErr := io.ReadAll(resp.Body)
+			if readErr != nil {
+				return nil, "", "", false, fmt.Errorf("error reading response from %s: %w", url, readErr)
+			}
+
+			return data, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), false, nil
+		}
+
+		retryAfter := resp.Header.Get("Retry-After")
+		resp.Body.Close()
+
+		if !isRetryableStatus(resp.StatusCode) || attempt >= maxRetries || !sleepBackoff(ctx, attempt, retryAfter) {
+			return nil, "", "", false, fmt.Errorf("unexpected status %s fetching %s", resp.Status, url) //nolint:goerr113
+		}
+	}
+}
+
+// isRetryableStatus reports whether a response status is worth retrying: a
+// transient server error, or a 429 asking the caller to slow down.
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// sleepBackoff waits before a retry, honoring a server-supplied Retry-After
+// (in seconds) when present and falling back to exponential backoff
+// otherwise. It returns false if ctx is done first.
+func sleepBackoff(ctx context.Context, attempt int, retryAfter string) bool {
+	delay := retryBaseDelay << attempt
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
+		delay = time.Duration(seconds) * time.Second
+	}
+
+	select {
+	case <-time.After(delay):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
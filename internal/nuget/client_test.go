@@ -0,0 +1,139 @@
+package nuget
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFindResourceExactMatch(t *testing.T) {
+	body := []byte(`{"resources":[
+		{"@id":"https://example.com/reg", "@type":"RegistrationsBaseUrl/3.6.0"},
+		{"@id":"https://example.com/reg-old", "@type":"RegistrationsBaseUrl/3.0.0"}
+	]}`)
+
+	if got := findResource(body, "RegistrationsBaseUrl/3.6.0"); got != "https://example.com/reg" {
+		t.Errorf("findResource exact = %q, want the exact-typed resource", got)
+	}
+}
+
+func TestFindResourceFallsBackToSharedPrefix(t *testing.T) {
+	body := []byte(`{"resources":[
+		{"@id":"https://example.com/reg-old", "@type":"RegistrationsBaseUrl/3.0.0"}
+	]}`)
+
+	if got := findResource(body, "RegistrationsBaseUrl/3.6.0"); got != "https://example.com/reg-old" {
+		t.Errorf("findResource fallback = %q, want the prefix-matching resource", got)
+	}
+}
+
+func TestFindResourceNoMatch(t *testing.T) {
+	body := []byte(`{"resources":[{"@id":"https://example.com/x", "@type":"SearchQueryService/3.0.0"}]}`)
+
+	if got := findResource(body, "RegistrationsBaseUrl/3.6.0"); got != "" {
+		t.Errorf("findResource = %q, want empty when nothing matches", got)
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	tests := []struct {
+		code int
+		want bool
+	}{
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+		{http.StatusOK, false},
+		{http.StatusNotFound, false},
+		{http.StatusBadRequest, false},
+	}
+
+	for _, test := range tests {
+		if got := isRetryableStatus(test.code); got != test.want {
+			t.Errorf("isRetryableStatus(%d) = %v, want %v", test.code, got, test.want)
+		}
+	}
+}
+
+// TestGetCachedRevalidates exercises a full cache round trip: the first
+// request gets a 200 with an ETag and populates the cache, the second sends
+// If-None-Match and the server answers 304, so the cached body is reused
+// without the handler ever serving it again.
+func TestGetCachedRevalidates(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{Sources: []Source{{Name: "test", URL: server.URL}}})
+	client.Cache = &Cache{dir: t.TempDir()}
+
+	source := Source{Name: "test", URL: server.URL}
+
+	first, err := client.getCached(context.Background(), source, server.URL, "Some.Package")
+	if err != nil {
+		t.Fatalf("first getCached: %v", err)
+	}
+
+	second, err := client.getCached(context.Background(), source, server.URL, "Some.Package")
+	if err != nil {
+		t.Fatalf("second getCached: %v", err)
+	}
+
+	if string(first) != string(second) {
+		t.Errorf("second getCached = %q, want the cached body %q", second, first)
+	}
+
+	if requests != 2 {
+		t.Errorf("handler invoked %d times, want exactly 2 (one fetch, one revalidation)", requests)
+	}
+}
+
+// TestDoGetRetriesOnServerError exercises the retry path: the first response
+// is a transient 503 and the second succeeds, so the caller should see the
+// successful body rather than an error.
+func TestDoGetRetriesOnServerError(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{Sources: []Source{{Name: "test", URL: server.URL}}})
+
+	body, err := client.get(context.Background(), Source{Name: "test", URL: server.URL}, server.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if string(body) != "ok" {
+		t.Errorf("get() = %q, want %q", body, "ok")
+	}
+
+	if requests != 2 {
+		t.Errorf("handler invoked %d times, want exactly 2 (one failure, one retry)", requests)
+	}
+}
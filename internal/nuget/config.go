@@ -0,0 +1,232 @@
+// Package nuget discovers NuGet v3 feed configuration (NuGet.Config package
+// sources, credentials and package source mapping) and queries the feeds'
+// registration resource for a package's known versions.
+package nuget
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// configFileNames are the file names NuGet looks for in each directory,
+// tried in order.
+var configFileNames = []string{"nuget.config", "NuGet.Config"} //nolint:gochecknoglobals
+
+// Source is a single configured package source.
+type Source struct {
+	Name string
+	URL  string
+}
+
+// Credential holds HTTP basic-auth credentials for a named package source,
+// e.g. a username plus an Azure DevOps PAT stored as ClearTextPassword.
+type Credential struct {
+	Username string
+	Password string
+}
+
+// Config is the merged view of every NuGet.Config found walking up from a
+// project directory: package sources, their credentials, and package source
+// mapping patterns.
+type Config struct {
+	Sources  []Source
+	Creds    map[string]Credential
+	Mappings map[string][]string // source name -> glob patterns
+}
+
+type configXML struct {
+	PackageSources struct {
+		Clear *struct{} `xml:"clear"`
+		Add   []struct {
+			Key   string `xml:"key,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"add"`
+	} `xml:"packageSources"`
+	PackageSourceCredentials struct {
+		Sources []struct {
+			XMLName xml.Name
+			Add     []struct {
+				Key   string `xml:"key,attr"`
+				Value string `xml:"value,attr"`
+			} `xml:"add"`
+		} `xml:",any"`
+	} `xml:"packageSourceCredentials"`
+	PackageSourceMapping struct {
+		PackageSource []struct {
+			Key     string `xml:"key,attr"`
+			Package []struct {
+				Pattern string `xml:"pattern,attr"`
+			} `xml:"package"`
+		} `xml:"packageSource"`
+	} `xml:"packageSourceMapping"`
+}
+
+// Load walks up from startDir collecting every NuGet.Config it finds, then
+// applies them furthest-first so files closer to the project win, mirroring
+// how `dotnet restore` resolves configuration: a <clear /> drops every source
+// contributed by directories above it, and a closer file's credentials or
+// packageSourceMapping override a farther one's. Falls back to nuget.org when
+// no configuration is found at all.
+func Load(startDir string) (*Config, error) {
+	var configPaths []string
+
+	dir := startDir
+	for {
+		if path, err := findConfigFile(dir); err != nil {
+			return nil, err
+		} else if path != "" {
+			configPaths = append(configPaths, path)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	cfg := &Config{Creds: map[string]Credential{}, Mappings: map[string][]string{}}
+
+	var sourceOrder []string
+
+	sourceURL := map[string]string{}
+
+	for i := len(configPaths) - 1; i >= 0; i-- {
+		data, err := os.ReadFile(configPaths[i])
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", configPaths[i], err)
+		}
+
+		var parsed configXML
+		if err := xml.Unmarshal(data, &parsed); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", configPaths[i], err)
+		}
+
+		if parsed.PackageSources.Clear != nil {
+			sourceOrder = nil
+			sourceURL = map[string]string{}
+		}
+
+		for _, add := range parsed.PackageSources.Add {
+			if _, ok := sourceURL[add.Key]; !ok {
+				sourceOrder = append(sourceOrder, add.Key)
+			}
+
+			sourceURL[add.Key] = expandEnv(add.Value)
+		}
+
+		for _, src := range parsed.PackageSourceCredentials.Sources {
+			var cred Credential
+			for _, add := range src.Add {
+				switch add.Key {
+				case "Username":
+					cred.Username = expandEnv(add.Value)
+				case "ClearTextPassword", "Password":
+					cred.Password = expandEnv(add.Value)
+				}
+			}
+
+			cfg.Creds[src.XMLName.Local] = cred
+		}
+
+		if len(parsed.PackageSourceMapping.PackageSource) > 0 {
+			cfg.Mappings = map[string][]string{}
+			for _, ps := range parsed.PackageSourceMapping.PackageSource {
+				for _, pkg := range ps.Package {
+					cfg.Mappings[ps.Key] = append(cfg.Mappings[ps.Key], pkg.Pattern)
+				}
+			}
+		}
+	}
+
+	for _, name := range sourceOrder {
+		cfg.Sources = append(cfg.Sources, Source{Name: name, URL: sourceURL[name]})
+	}
+
+	if len(cfg.Sources) == 0 {
+		cfg.Sources = []Source{{Name: "nuget.org", URL: "https://api.nuget.org/v3/index.json"}}
+	}
+
+	return cfg, nil
+}
+
+// findConfigFile returns the first matching config file name present in dir,
+// or "" if none exists there.
+func findConfigFile(dir string) (string, error) {
+	for _, name := range configFileNames {
+		candidate := filepath.Join(dir, name)
+
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		} else if !os.IsNotExist(err) {
+			return "", fmt.Errorf("error checking for %s: %w", candidate, err)
+		}
+	}
+
+	return "", nil
+}
+
+var envVarPattern = regexp.MustCompile(`%([^%]+)%`) //nolint:gochecknoglobals
+
+// expandEnv expands NuGet.Config's %NAME% environment variable references,
+// leaving references to unset variables untouched.
+func expandEnv(value string) string {
+	return envVarPattern.ReplaceAllStringFunc(value, func(match string) string {
+		name := strings.Trim(match, "%")
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+
+		return match
+	})
+}
+
+// SourcesForPackage returns the sources id should be queried against. When
+// packageSourceMapping is configured, id is resolved against the single
+// most-specific matching pattern length (longest pattern wins; every source
+// tied at that length is returned, in configured order) and only the
+// source(s) owning such a pattern are returned
+// — mirroring NuGet's own exclusionary, single-owner behavior, a catch-all
+// "*" mapped to a public source never shadows a more specific private-feed
+// mapping, and an id matching no pattern resolves against *no* source rather
+// than falling back to every feed. With no mapping configured at all, every
+// source is returned.
+func (c *Config) SourcesForPackage(id string) []Source {
+	if len(c.Mappings) == 0 {
+		return c.Sources
+	}
+
+	var (
+		matched         []Source
+		bestSpecificity = -1
+	)
+
+	for _, src := range c.Sources {
+		specificity := -1
+
+		for _, pattern := range c.Mappings[src.Name] {
+			if ok, _ := path.Match(pattern, id); ok && len(pattern) > specificity {
+				specificity = len(pattern)
+			}
+		}
+
+		if specificity < 0 {
+			continue
+		}
+
+		switch {
+		case specificity > bestSpecificity:
+			bestSpecificity = specificity
+			matched = []Source{src}
+		case specificity == bestSpecificity:
+			matched = append(matched, src)
+		}
+	}
+
+	return matched
+}
@@ -0,0 +1,147 @@
+package nuget
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, dir, name, contents string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestLoadNoConfigFallsBackToNugetOrg(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if len(cfg.Sources) != 1 || cfg.Sources[0].Name != "nuget.org" {
+		t.Fatalf("Sources = %+v, want the nuget.org fallback", cfg.Sources)
+	}
+}
+
+func TestLoadMergesParentAndChildSources(t *testing.T) {
+	root := t.TempDir()
+	writeConfig(t, root, "nuget.config", `<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+  </packageSources>
+</configuration>`)
+
+	project := filepath.Join(root, "src", "app")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	writeConfig(t, project, "nuget.config", `<configuration>
+  <packageSources>
+    <add key="internal" value="https://feeds.example.com/v3/index.json" />
+  </packageSources>
+</configuration>`)
+
+	cfg, err := Load(project)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if len(cfg.Sources) != 2 {
+		t.Fatalf("Sources = %+v, want both the parent and child entries", cfg.Sources)
+	}
+
+	if cfg.Sources[0].Name != "nuget.org" || cfg.Sources[1].Name != "internal" {
+		t.Errorf("Sources = %+v, want nuget.org (furthest) before internal (closest)", cfg.Sources)
+	}
+}
+
+func TestLoadClearDropsParentSources(t *testing.T) {
+	root := t.TempDir()
+	writeConfig(t, root, "nuget.config", `<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+  </packageSources>
+</configuration>`)
+
+	project := filepath.Join(root, "app")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	writeConfig(t, project, "nuget.config", `<configuration>
+  <packageSources>
+    <clear />
+    <add key="internal" value="https://feeds.example.com/v3/index.json" />
+  </packageSources>
+</configuration>`)
+
+	cfg, err := Load(project)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if len(cfg.Sources) != 1 || cfg.Sources[0].Name != "internal" {
+		t.Fatalf("Sources = %+v, want only the post-<clear/> internal source", cfg.Sources)
+	}
+}
+
+func TestSourcesForPackageNoMapping(t *testing.T) {
+	cfg := &Config{Sources: []Source{{Name: "nuget.org"}, {Name: "internal"}}}
+
+	sources := cfg.SourcesForPackage("Any.Package")
+	if len(sources) != 2 {
+		t.Errorf("SourcesForPackage = %+v, want every source when no mapping is configured", sources)
+	}
+}
+
+func TestSourcesForPackageMatchedMapping(t *testing.T) {
+	cfg := &Config{
+		Sources: []Source{{Name: "nuget.org"}, {Name: "internal"}},
+		Mappings: map[string][]string{
+			"internal":  {"Contoso.*"},
+			"nuget.org": {"*"},
+		},
+	}
+
+	sources := cfg.SourcesForPackage("Contoso.Utils")
+
+	if len(sources) != 1 || sources[0].Name != "internal" {
+		t.Fatalf("SourcesForPackage = %+v, want only the more specific \"internal\" source to match", sources)
+	}
+}
+
+func TestSourcesForPackageUnmatchedFallsThroughToCatchAll(t *testing.T) {
+	cfg := &Config{
+		Sources: []Source{{Name: "nuget.org"}, {Name: "internal"}},
+		Mappings: map[string][]string{
+			"internal":  {"Contoso.*"},
+			"nuget.org": {"*"},
+		},
+	}
+
+	sources := cfg.SourcesForPackage("Newtonsoft.Json")
+
+	if len(sources) != 1 || sources[0].Name != "nuget.org" {
+		t.Fatalf("SourcesForPackage = %+v, want only the catch-all \"nuget.org\" source to match", sources)
+	}
+}
+
+func TestSourcesForPackageUnmappedIsExcludedNotFallback(t *testing.T) {
+	cfg := &Config{
+		Sources: []Source{{Name: "nuget.org"}, {Name: "internal"}},
+		Mappings: map[string][]string{
+			"internal": {"Contoso.*"},
+		},
+	}
+
+	sources := cfg.SourcesForPackage("Some.Typo.Package")
+
+	if len(sources) != 0 {
+		t.Errorf("SourcesForPackage = %+v, want no sources for an id matching no mapping pattern", sources)
+	}
+}
@@ -0,0 +1,136 @@
+package report
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"sort"
+)
+
+// nuspecXML is the minimal shape of a .nuspec this package needs: its flat
+// dependency list (older packages) and/or per-framework dependency groups
+// (everything since NuGet 2.x).
+type nuspecXML struct {
+	Metadata struct {
+		Dependencies struct {
+			Dependency []nuspecDependency `xml:"dependency"`
+			Group      []struct {
+				TargetFramework string             `xml:"targetFramework,attr"`
+				Dependency      []nuspecDependency `xml:"dependency"`
+			} `xml:"group"`
+		} `xml:"dependencies"`
+	} `xml:"metadata"`
+}
+
+type nuspecDependency struct {
+	ID      string `xml:"id,attr"`
+	Version string `xml:"version,attr"`
+}
+
+// parsedNuspec is a nuspec reduced to what the diff needs: every dependency
+// id -> version (flattened across frameworks; nugbot doesn't currently track
+// per-framework version pins) and the set of target frameworks it declares
+// dependency groups for.
+type parsedNuspec struct {
+	dependencies map[string]string
+	frameworks   map[string]bool
+}
+
+func parseNuspec(data []byte) (parsedNuspec, error) {
+	var doc nuspecXML
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return parsedNuspec{}, fmt.Errorf("error parsing .nuspec: %w", err)
+	}
+
+	parsed := parsedNuspec{dependencies: map[string]string{}, frameworks: map[string]bool{}}
+
+	for _, dep := range doc.Metadata.Dependencies.Dependency {
+		parsed.dependencies[dep.ID] = dep.Version
+	}
+
+	for _, group := range doc.Metadata.Dependencies.Group {
+		if group.TargetFramework != "" {
+			parsed.frameworks[group.TargetFramework] = true
+		}
+
+		for _, dep := range group.Dependency {
+			parsed.dependencies[dep.ID] = dep.Version
+		}
+	}
+
+	return parsed, nil
+}
+
+// annotateNuspecDiff fetches the .nuspec for entry's current and new
+// versions and fills in its dependency deltas and framework warnings.
+func annotateNuspecDiff(ctx context.Context, fetcher NuspecFetcher, entry *Entry) error {
+	oldData, err := fetcher.FetchNuspec(ctx, entry.Update.Include, entry.Update.CurrentVersion)
+	if err != nil {
+		return err
+	}
+
+	newData, err := fetcher.FetchNuspec(ctx, entry.Update.Include, entry.Update.NewVersion)
+	if err != nil {
+		return err
+	}
+
+	oldSpec, err := parseNuspec(oldData)
+	if err != nil {
+		return err
+	}
+
+	newSpec, err := parseNuspec(newData)
+	if err != nil {
+		return err
+	}
+
+	diffDependencies(entry, oldSpec, newSpec)
+	diffFrameworks(entry, oldSpec, newSpec)
+
+	return nil
+}
+
+func diffDependencies(entry *Entry, oldSpec, newSpec parsedNuspec) {
+	for _, id := range sortedKeys(newSpec.dependencies) {
+		newVersion := newSpec.dependencies[id]
+
+		oldVersion, existed := oldSpec.dependencies[id]
+		switch {
+		case !existed:
+			entry.AddedDependencies = append(entry.AddedDependencies, DependencyChange{ID: id, ToVersion: newVersion})
+		case oldVersion != newVersion:
+			entry.ChangedDependencies = append(entry.ChangedDependencies,
+				DependencyChange{ID: id, FromVersion: oldVersion, ToVersion: newVersion})
+		}
+	}
+
+	for _, id := range sortedKeys(oldSpec.dependencies) {
+		if _, stillPresent := newSpec.dependencies[id]; !stillPresent {
+			entry.RemovedDependencies = append(entry.RemovedDependencies,
+				DependencyChange{ID: id, FromVersion: oldSpec.dependencies[id]})
+		}
+	}
+}
+
+func diffFrameworks(entry *Entry, oldSpec, newSpec parsedNuspec) {
+	for _, framework := range sortedKeys(oldSpec.frameworks) {
+		if !newSpec.frameworks[framework] {
+			entry.Warnings = append(entry.Warnings,
+				fmt.Sprintf("drops support for target framework %s", framework))
+		}
+	}
+}
+
+// sortedKeys returns m's keys in ascending order so diff output (JSON and
+// Markdown) is deterministic across runs instead of varying with Go's
+// randomized map iteration.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
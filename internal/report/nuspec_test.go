@@ -0,0 +1,97 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffDependenciesIsDeterministic(t *testing.T) {
+	oldSpec := parsedNuspec{dependencies: map[string]string{
+		"Zebra.Pkg":   "1.0.0",
+		"Alpha.Pkg":   "2.0.0",
+		"Mango.Pkg":   "1.0.0",
+		"Beta.Pkg":    "1.0.0",
+		"Charlie.Lib": "1.0.0",
+	}}
+	newSpec := parsedNuspec{dependencies: map[string]string{
+		"Zebra.Pkg": "1.1.0",
+		"Alpha.Pkg": "2.0.0",
+		"Mango.Pkg": "1.0.0",
+		"Delta.Pkg": "1.0.0",
+	}}
+
+	var first *Entry
+
+	for i := 0; i < 20; i++ {
+		entry := &Entry{}
+		diffDependencies(entry, oldSpec, newSpec)
+
+		if first == nil {
+			first = entry
+
+			continue
+		}
+
+		if !reflect.DeepEqual(first.AddedDependencies, entry.AddedDependencies) ||
+			!reflect.DeepEqual(first.RemovedDependencies, entry.RemovedDependencies) ||
+			!reflect.DeepEqual(first.ChangedDependencies, entry.ChangedDependencies) {
+			t.Fatalf("diffDependencies produced non-deterministic ordering across runs:\nfirst=%+v\nlater=%+v", first, entry)
+		}
+	}
+
+	wantAdded := []DependencyChange{{ID: "Delta.Pkg", ToVersion: "1.0.0"}}
+	wantRemoved := []DependencyChange{
+		{ID: "Beta.Pkg", FromVersion: "1.0.0"},
+		{ID: "Charlie.Lib", FromVersion: "1.0.0"},
+	}
+	wantChanged := []DependencyChange{{ID: "Zebra.Pkg", FromVersion: "1.0.0", ToVersion: "1.1.0"}}
+
+	if !reflect.DeepEqual(first.AddedDependencies, wantAdded) {
+		t.Errorf("AddedDependencies = %+v, want %+v", first.AddedDependencies, wantAdded)
+	}
+
+	if !reflect.DeepEqual(first.RemovedDependencies, wantRemoved) {
+		t.Errorf("RemovedDependencies = %+v, want %+v", first.RemovedDependencies, wantRemoved)
+	}
+
+	if !reflect.DeepEqual(first.ChangedDependencies, wantChanged) {
+		t.Errorf("ChangedDependencies = %+v, want %+v", first.ChangedDependencies, wantChanged)
+	}
+}
+
+func TestDiffFrameworksIsDeterministic(t *testing.T) {
+	oldSpec := parsedNuspec{frameworks: map[string]bool{
+		"net48":          true,
+		"netstandard2.0": true,
+		"net6.0":         true,
+	}}
+	newSpec := parsedNuspec{frameworks: map[string]bool{
+		"net6.0": true,
+	}}
+
+	var first *Entry
+
+	for i := 0; i < 20; i++ {
+		entry := &Entry{}
+		diffFrameworks(entry, oldSpec, newSpec)
+
+		if first == nil {
+			first = entry
+
+			continue
+		}
+
+		if !reflect.DeepEqual(first.Warnings, entry.Warnings) {
+			t.Fatalf("diffFrameworks produced non-deterministic ordering across runs:\nfirst=%+v\nlater=%+v", first.Warnings, entry.Warnings)
+		}
+	}
+
+	want := []string{
+		"drops support for target framework net48",
+		"drops support for target framework netstandard2.0",
+	}
+
+	if !reflect.DeepEqual(first.Warnings, want) {
+		t.Errorf("Warnings = %+v, want %+v", first.Warnings, want)
+	}
+}
@@ -0,0 +1,79 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// classTitles is the Markdown section heading for each class.
+var classTitles = map[Class]string{ //nolint:gochecknoglobals
+	ClassMajor:      "Major",
+	ClassMinor:      "Minor",
+	ClassPatch:      "Patch",
+	ClassPrerelease: "Prerelease",
+}
+
+// JSON renders the report grouped by class, omitting empty groups.
+func (r *Report) JSON() ([]byte, error) {
+	grouped := make(map[Class][]Entry, len(classOrder))
+
+	for _, class := range classOrder {
+		if entries := r.Groups[class]; len(entries) > 0 {
+			grouped[class] = entries
+		}
+	}
+
+	out, err := json.MarshalIndent(grouped, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling report: %w", err)
+	}
+
+	return out, nil
+}
+
+// Markdown renders a PR-ready summary in classOrder: major, minor, patch,
+// then prerelease.
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString("# Package updates\n\n")
+
+	for _, class := range classOrder {
+		entries := r.Groups[class]
+		if len(entries) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&sb, "## %s\n\n", classTitles[class])
+
+		for _, entry := range entries {
+			fmt.Fprintf(&sb, "- **%s** %s → %s\n", entry.Update.Include, entry.Update.CurrentVersion, entry.Update.NewVersion)
+
+			for _, dep := range entry.AddedDependencies {
+				fmt.Fprintf(&sb, "  - + now depends on %s %s\n", dep.ID, dep.ToVersion)
+			}
+
+			for _, dep := range entry.RemovedDependencies {
+				fmt.Fprintf(&sb, "  - − no longer depends on %s %s\n", dep.ID, dep.FromVersion)
+			}
+
+			for _, dep := range entry.ChangedDependencies {
+				fmt.Fprintf(&sb, "  - %s %s → %s\n", dep.ID, dep.FromVersion, dep.ToVersion)
+			}
+
+			for _, warning := range entry.Warnings {
+				fmt.Fprintf(&sb, "  - ⚠ %s\n", warning)
+			}
+		}
+
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// HasClass reports whether the report contains at least one entry in class.
+func (r *Report) HasClass(class Class) bool {
+	return len(r.Groups[class]) > 0
+}
@@ -0,0 +1,86 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONOmitsEmptyClasses(t *testing.T) {
+	rpt := &Report{Groups: map[Class][]Entry{
+		ClassPatch: {{Update: Update{Include: "Foo", CurrentVersion: "1.0.0", NewVersion: "1.0.1"}, Class: ClassPatch}},
+	}}
+
+	out, err := rpt.JSON()
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+
+	got := string(out)
+
+	if !strings.Contains(got, `"patch"`) {
+		t.Errorf("JSON() = %s, want a patch key", got)
+	}
+
+	for _, absent := range []string{`"major"`, `"minor"`, `"prerelease"`} {
+		if strings.Contains(got, absent) {
+			t.Errorf("JSON() = %s, want no %s key for an empty class", got, absent)
+		}
+	}
+}
+
+func TestMarkdownOrdersMostDisruptiveFirst(t *testing.T) {
+	rpt := &Report{Groups: map[Class][]Entry{
+		ClassPatch: {{Update: Update{Include: "Patch.Pkg", CurrentVersion: "1.0.0", NewVersion: "1.0.1"}, Class: ClassPatch}},
+		ClassMajor: {{Update: Update{Include: "Major.Pkg", CurrentVersion: "1.0.0", NewVersion: "2.0.0"}, Class: ClassMajor}},
+	}}
+
+	md := rpt.Markdown()
+
+	majorIdx := strings.Index(md, "Major.Pkg")
+	patchIdx := strings.Index(md, "Patch.Pkg")
+
+	if majorIdx == -1 || patchIdx == -1 || majorIdx > patchIdx {
+		t.Errorf("Markdown() did not put the Major section before Patch, got:\n%s", md)
+	}
+
+	if !strings.Contains(md, "## Major") || !strings.Contains(md, "## Patch") {
+		t.Errorf("Markdown() missing class headings, got:\n%s", md)
+	}
+}
+
+func TestMarkdownRendersDependencyAndWarningLines(t *testing.T) {
+	rpt := &Report{Groups: map[Class][]Entry{
+		ClassMinor: {{
+			Update:              Update{Include: "Foo", CurrentVersion: "1.0.0", NewVersion: "1.1.0"},
+			Class:               ClassMinor,
+			AddedDependencies:   []DependencyChange{{ID: "Added.Pkg", ToVersion: "1.0.0"}},
+			RemovedDependencies: []DependencyChange{{ID: "Removed.Pkg", FromVersion: "1.0.0"}},
+			ChangedDependencies: []DependencyChange{{ID: "Changed.Pkg", FromVersion: "1.0.0", ToVersion: "2.0.0"}},
+			Warnings:            []string{"drops support for target framework net48"},
+		}},
+	}}
+
+	md := rpt.Markdown()
+
+	for _, want := range []string{
+		"Foo** 1.0.0 → 1.1.0",
+		"Added.Pkg",
+		"Removed.Pkg",
+		"Changed.Pkg 1.0.0 → 2.0.0",
+		"drops support for target framework net48",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q, got:\n%s", want, md)
+		}
+	}
+}
+
+func TestHasClassTrueWhenPresent(t *testing.T) {
+	rpt := &Report{Groups: map[Class][]Entry{
+		ClassMajor: {{Class: ClassMajor}},
+	}}
+
+	if !rpt.HasClass(ClassMajor) {
+		t.Errorf("HasClass(ClassMajor) = false, want true")
+	}
+}
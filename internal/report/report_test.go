@@ -0,0 +1,147 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestClassifyPatch(t *testing.T) {
+	class := classify(Update{CurrentVersion: "1.2.3", NewVersion: "1.2.4"})
+
+	if class != ClassPatch {
+		t.Errorf("classify(1.2.3 -> 1.2.4) = %v, want ClassPatch", class)
+	}
+}
+
+func TestClassifyMinor(t *testing.T) {
+	class := classify(Update{CurrentVersion: "1.2.3", NewVersion: "1.3.0"})
+
+	if class != ClassMinor {
+		t.Errorf("classify(1.2.3 -> 1.3.0) = %v, want ClassMinor", class)
+	}
+}
+
+func TestClassifyMajor(t *testing.T) {
+	class := classify(Update{CurrentVersion: "1.2.3", NewVersion: "2.0.0"})
+
+	if class != ClassMajor {
+		t.Errorf("classify(1.2.3 -> 2.0.0) = %v, want ClassMajor", class)
+	}
+}
+
+func TestClassifySamePrereleaseBaseIsPrerelease(t *testing.T) {
+	class := classify(Update{CurrentVersion: "1.2.3-preview.1", NewVersion: "1.2.3-preview.2"})
+
+	if class != ClassPrerelease {
+		t.Errorf("classify(1.2.3-preview.1 -> 1.2.3-preview.2) = %v, want ClassPrerelease", class)
+	}
+}
+
+func TestClassifyPrereleaseToStableOnSameBaseIsPrerelease(t *testing.T) {
+	class := classify(Update{CurrentVersion: "1.2.3-preview.1", NewVersion: "1.2.3"})
+
+	if class != ClassPrerelease {
+		t.Errorf("classify(1.2.3-preview.1 -> 1.2.3) = %v, want ClassPrerelease", class)
+	}
+}
+
+func TestClassifyPrereleaseOnADifferentBaseIsMajorOrMinor(t *testing.T) {
+	class := classify(Update{CurrentVersion: "1.2.3-preview.1", NewVersion: "2.0.0-preview.1"})
+
+	if class != ClassMajor {
+		t.Errorf("classify(1.2.3-preview.1 -> 2.0.0-preview.1) = %v, want ClassMajor", class)
+	}
+}
+
+func TestClassifyUnparseableVersionFallsBackToPatch(t *testing.T) {
+	class := classify(Update{CurrentVersion: "not-a-version", NewVersion: "1.0.0"})
+
+	if class != ClassPatch {
+		t.Errorf("classify(unparseable) = %v, want ClassPatch", class)
+	}
+}
+
+// fakeFetcher is a NuspecFetcher double that serves canned nuspec bodies
+// keyed by "id@version", or errors when the key is missing.
+type fakeFetcher struct {
+	nuspecs map[string][]byte
+}
+
+func (f *fakeFetcher) FetchNuspec(_ context.Context, packageID, version string) ([]byte, error) {
+	data, ok := f.nuspecs[packageID+"@"+version]
+	if !ok {
+		return nil, errors.New("no nuspec for " + packageID + "@" + version) //nolint:goerr113
+	}
+
+	return data, nil
+}
+
+func nuspec(deps ...string) []byte {
+	body := "<package><metadata><dependencies>"
+	for _, d := range deps {
+		body += `<dependency id="` + d + `" version="1.0.0" />`
+	}
+
+	return []byte(body + "</dependencies></metadata></package>")
+}
+
+func TestBuildClassifiesAndGroupsInUpdateOrder(t *testing.T) {
+	updates := []Update{
+		{Include: "Major.Pkg", CurrentVersion: "1.0.0", NewVersion: "2.0.0"},
+		{Include: "Patch.Pkg", CurrentVersion: "1.0.0", NewVersion: "1.0.1"},
+		{Include: "Minor.Pkg", CurrentVersion: "1.0.0", NewVersion: "1.1.0"},
+	}
+
+	rpt := Build(context.Background(), nil, updates, 4)
+
+	if len(rpt.Entries) != 3 {
+		t.Fatalf("len(Entries) = %d, want 3", len(rpt.Entries))
+	}
+
+	for i, update := range updates {
+		if rpt.Entries[i].Update != update {
+			t.Errorf("Entries[%d].Update = %+v, want %+v (order not preserved)", i, rpt.Entries[i].Update, update)
+		}
+	}
+
+	if !rpt.HasClass(ClassMajor) || !rpt.HasClass(ClassMinor) || !rpt.HasClass(ClassPatch) {
+		t.Errorf("Groups = %+v, want an entry in each of major/minor/patch", rpt.Groups)
+	}
+}
+
+func TestBuildAnnotatesNuspecDiffWhenFetcherSet(t *testing.T) {
+	fetcher := &fakeFetcher{nuspecs: map[string][]byte{
+		"Foo@1.0.0": nuspec("Bar"),
+		"Foo@1.1.0": nuspec("Bar", "Baz"),
+	}}
+
+	updates := []Update{{Include: "Foo", CurrentVersion: "1.0.0", NewVersion: "1.1.0"}}
+
+	rpt := Build(context.Background(), fetcher, updates, 2)
+
+	entry := rpt.Entries[0]
+	if len(entry.AddedDependencies) != 1 || entry.AddedDependencies[0].ID != "Baz" {
+		t.Errorf("AddedDependencies = %+v, want Baz added", entry.AddedDependencies)
+	}
+}
+
+func TestBuildRecordsWarningWhenNuspecFetchFails(t *testing.T) {
+	fetcher := &fakeFetcher{nuspecs: map[string][]byte{}}
+
+	updates := []Update{{Include: "Foo", CurrentVersion: "1.0.0", NewVersion: "1.1.0"}}
+
+	rpt := Build(context.Background(), fetcher, updates, 2)
+
+	if len(rpt.Entries[0].Warnings) == 0 {
+		t.Errorf("Warnings = %+v, want a warning recorded for the failed fetch", rpt.Entries[0].Warnings)
+	}
+}
+
+func TestHasClassFalseWhenEmpty(t *testing.T) {
+	rpt := &Report{Groups: map[Class][]Entry{}}
+
+	if rpt.HasClass(ClassMajor) {
+		t.Errorf("HasClass(ClassMajor) = true, want false for an empty report")
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// packagesLockFile is the well-known file name `dotnet restore --use-lock-file`
+// writes next to the .csproj it locks.
+const packagesLockFile = "packages.lock.json"
+
+// resolvedFieldPattern and requestedFieldPattern match a package entry's
+// "resolved"/"requested" field, scoped (via rewriteLockEntries) to that
+// entry's own brace-balanced block so a coincidentally same-named key in a
+// sibling "dependencies" sub-object is never touched.
+var (
+	resolvedFieldPattern  = regexp.MustCompile(`"resolved"\s*:\s*"[^"]*"`)    //nolint:gochecknoglobals
+	requestedFieldPattern = regexp.MustCompile(`"requested"\s*:\s*"([^"]*)"`) //nolint:gochecknoglobals
+)
+
+// updatePackagesLockFile patches the "resolved" (and, where it's a plain
+// pinned version rather than a range, "requested") fields of any
+// packages.lock.json entry matching one of updates. Like updateCsprojFile, it
+// never round-trips through encoding/json's marshaller: Go marshals map keys
+// alphabetically, which would reorder every framework, package and field in
+// the file and turn a two-version bump into a whole-file diff in a file
+// that's normally committed and reviewed. Instead it locates each matching
+// package entry's exact byte range and patches only those two fields within
+// it, leaving every other byte — including key order — untouched. It's a
+// no-op when no lock file sits next to csprojPath.
+func updatePackagesLockFile(csprojPath string, updates []PackageUpdate, dryRun bool, diffs *strings.Builder) error {
+	lockPath := filepath.Join(filepath.Dir(csprojPath), packagesLockFile)
+
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error reading %s: %w", packagesLockFile, err)
+	}
+
+	versions := make(map[string]string, len(updates))
+	for _, update := range updates {
+		versions[update.Include] = update.NewVersion
+	}
+
+	updated, err := rewriteLockEntries(data, versions)
+	if err != nil {
+		return fmt.Errorf("error rewriting %s: %w", packagesLockFile, err)
+	}
+
+	return writeOrDiff(lockPath, data, updated, dryRun, diffs)
+}
+
+// rewriteLockEntries finds each `"<name>": { ... }` package entry in a
+// packages.lock.json's raw bytes whose name is a key in versions — under any
+// framework, since the same package can be locked for several target
+// frameworks — and rewrites its resolved/requested fields in place.
+func rewriteLockEntries(data []byte, versions map[string]string) ([]byte, error) {
+	type edit struct {
+		start, end int
+		newBytes   []byte
+	}
+
+	var edits []edit
+
+	for name, newVersion := range versions {
+		pattern := regexp.MustCompile(`"` + regexp.QuoteMeta(name) + `"\s*:\s*\{`)
+
+		for _, loc := range pattern.FindAllIndex(data, -1) {
+			openBrace := loc[1] - 1
+
+			closeBrace, err := matchingBrace(data, openBrace)
+			if err != nil {
+				return nil, err
+			}
+
+			entry := data[loc[0] : closeBrace+1]
+
+			rewritten := rewriteLockEntry(entry, newVersion)
+			if bytes.Equal(rewritten, entry) {
+				continue
+			}
+
+			edits = append(edits, edit{start: loc[0], end: closeBrace + 1, newBytes: rewritten})
+		}
+	}
+
+	if len(edits) == 0 {
+		return data, nil
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var out bytes.Buffer
+
+	cursor := 0
+	for _, e := range edits {
+		out.Write(data[cursor:e.start])
+		out.Write(e.newBytes)
+		cursor = e.end
+	}
+	out.Write(data[cursor:])
+
+	return out.Bytes(), nil
+}
+
+// rewriteLockEntry patches a single package entry's resolved field, and its
+// requested field too when that's a plain pinned version rather than a range
+// (a range like "[13.0.1, )" should stay as the dependency declared it).
+func rewriteLockEntry(entry []byte, newVersion string) []byte {
+	entry = resolvedFieldPattern.ReplaceAll(entry, []byte(fmt.Sprintf(`"resolved": "%s"`, newVersion)))
+
+	if m := requestedFieldPattern.FindSubmatch(entry); m != nil && !strings.ContainsAny(string(m[1]), "[(*,") {
+		entry = requestedFieldPattern.ReplaceAll(entry, []byte(fmt.Sprintf(`"requested": "%s"`, newVersion)))
+	}
+
+	return entry
+}
+
+// matchingBrace returns the index of the '}' that closes the '{' at
+// data[open], skipping over brace characters inside quoted strings (and
+// their escaped quotes) so a value like a contentHash never miscounts depth.
+func matchingBrace(data []byte, open int) (int, error) {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for i := open; i < len(data); i++ {
+		b := data[i]
+
+		switch {
+		case escaped:
+			escaped = false
+		case inString && b == '\\':
+			escaped = true
+		case b == '"':
+			inString = !inString
+		case inString:
+			// Inside a string; braces here don't affect nesting.
+		case b == '{':
+			depth++
+		case b == '}':
+			depth--
+
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("unbalanced braces in %s", packagesLockFile) //nolint:goerr113
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRewriteLockEntries(t *testing.T) {
+	data := []byte(`{
+  "version": 1,
+  "dependencies": {
+    "net8.0": {
+      "Newtonsoft.Json": {
+        "type": "Direct",
+        "requested": "[13.0.1, )",
+        "resolved": "13.0.1",
+        "contentHash": "abc=="
+      },
+      "Other.Pkg": {
+        "type": "Transitive",
+        "resolved": "2.0.0",
+        "dependencies": {
+          "Newtonsoft.Json": "13.0.1"
+        }
+      }
+    }
+  }
+}`)
+
+	out, err := rewriteLockEntries(data, map[string]string{"Newtonsoft.Json": "13.0.3"})
+	if err != nil {
+		t.Fatalf("rewriteLockEntries: %v", err)
+	}
+
+	got := string(out)
+
+	if !strings.Contains(got, `"resolved": "13.0.3"`) {
+		t.Errorf("expected top-level entry's resolved to be rewritten, got:\n%s", got)
+	}
+
+	if !strings.Contains(got, `"requested": "[13.0.1, )"`) {
+		t.Errorf("range requested should be left alone, got:\n%s", got)
+	}
+
+	if !strings.Contains(got, `"Newtonsoft.Json": "13.0.1"`) {
+		t.Errorf("nested transitive dependency entry should not be touched, got:\n%s", got)
+	}
+
+	if !strings.Contains(got, `"resolved": "2.0.0"`) {
+		t.Errorf("unrelated package's resolved field should be untouched, got:\n%s", got)
+	}
+}
+
+func TestRewriteLockEntriesPinnedRequested(t *testing.T) {
+	data := []byte(`{
+  "dependencies": {
+    "net8.0": {
+      "Some.Pkg": {
+        "type": "Direct",
+        "requested": "1.0.0",
+        "resolved": "1.0.0"
+      }
+    }
+  }
+}`)
+
+	out, err := rewriteLockEntries(data, map[string]string{"Some.Pkg": "1.1.0"})
+	if err != nil {
+		t.Fatalf("rewriteLockEntries: %v", err)
+	}
+
+	got := string(out)
+
+	if !strings.Contains(got, `"requested": "1.1.0"`) {
+		t.Errorf("plain pinned requested should be rewritten, got:\n%s", got)
+	}
+
+	if !strings.Contains(got, `"resolved": "1.1.0"`) {
+		t.Errorf("resolved should be rewritten, got:\n%s", got)
+	}
+}
+
+func TestRewriteLockEntriesNoMatchIsNoop(t *testing.T) {
+	data := []byte(`{"dependencies":{"net8.0":{"Some.Pkg":{"resolved":"1.0.0"}}}}`)
+
+	out, err := rewriteLockEntries(data, map[string]string{"Other.Pkg": "2.0.0"})
+	if err != nil {
+		t.Fatalf("rewriteLockEntries: %v", err)
+	}
+
+	if string(out) != string(data) {
+		t.Errorf("expected unchanged bytes, got:\n%s", out)
+	}
+}
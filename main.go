@@ -1,19 +1,25 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
-	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/spf13/cobra"
 	"github.com/tidwall/gjson"
+
+	"github.com/inovalon/dependabot/internal/nuget"
+	"github.com/inovalon/dependabot/internal/report"
 )
 
 // Project struct for parsing .csproj XML
@@ -28,7 +34,46 @@ type Project struct {
 // Package struct for parsing .csproj XML
 type Package struct {
 	Include string `xml:"Include,attr"` //nolint:tagliatelle
+	Update  string `xml:"Update,attr"`  //nolint:tagliatelle
 	Version string `xml:"Version,attr"` //nolint:tagliatelle
+
+	// Name is the package id, populated from Include or, for elements that
+	// only override a version pinned elsewhere (a common pattern in
+	// Directory.Build.props), from Update.
+	Name string `xml:"-"`
+	// VersionSource records which file the Version value actually came from,
+	// so an update can be written back to the file that owns it.
+	VersionSource VersionSource `xml:"-"`
+	// Spec is the parsed form of Version, covering exact, floating and range
+	// syntax.
+	Spec VersionSpec `xml:"-"`
+}
+
+// VersionSource identifies which file a PackageReference's effective version
+// was sourced from.
+type VersionSource int
+
+const (
+	// VersionSourceCsproj means the Version attribute lives on the
+	// PackageReference element itself.
+	VersionSourceCsproj VersionSource = iota
+	// VersionSourceCPM means the version came from a central
+	// Directory.Packages.props file (NuGet Central Package Management).
+	VersionSourceCPM
+)
+
+// centralPackagePropsFile is the well-known file name MSBuild looks for when
+// Central Package Management is enabled.
+const centralPackagePropsFile = "Directory.Packages.props"
+
+// PackagesProps mirrors the minimal shape of a Directory.Packages.props file
+// used for NuGet Central Package Management.
+type PackagesProps struct {
+	XMLName   xml.Name `xml:"Project"` //nolint:tagliatelle
+	ItemGroup []struct {
+		XMLName        xml.Name  `xml:"ItemGroup"`      //nolint:tagliatelle
+		PackageVersion []Package `xml:"PackageVersion"` //nolint:tagliatelle
+	} `xml:"ItemGroup"` //nolint:tagliatelle
 }
 
 // PackageUpdate struct for storing package update info
@@ -36,12 +81,44 @@ type PackageUpdate struct {
 	Include        string `json:"include,omitempty"`
 	CurrentVersion string `json:"current_version,omitempty"`
 	NewVersion     string `json:"new_version,omitempty"`
+
+	// VersionSource is which file owns the version and therefore needs the
+	// rewrite: the .csproj itself, or a central Directory.Packages.props.
+	VersionSource VersionSource `json:"-"`
 }
 
+// Prerelease modes accepted by the --prerelease flag.
+const (
+	// PrereleaseNone never proposes a prerelease version (the default).
+	PrereleaseNone = "none"
+	// PrereleaseSameTrack only proposes a later prerelease when the current
+	// pinned version is itself a prerelease on the same base version.
+	PrereleaseSameTrack = "same-track"
+	// PrereleaseAny proposes any prerelease that otherwise satisfies
+	// --update-type, regardless of what's currently pinned.
+	PrereleaseAny = "any"
+)
+
+// Report formats accepted by the --report flag.
+const (
+	ReportNone     = "none"
+	ReportJSON     = "json"
+	ReportMarkdown = "markdown"
+)
+
+// defaultConcurrency is how many packages' registries are checked at once.
+const defaultConcurrency = 8
+
 // updateType is the type of update to check for (major, minor, patch)
 var (
-	updateType string //nolint:gochecknoglobals
-	fix        bool   //nolint:gochecknoglobals
+	updateType   string //nolint:gochecknoglobals
+	prerelease   string //nolint:gochecknoglobals
+	fix          bool   //nolint:gochecknoglobals
+	dryRun       bool   //nolint:gochecknoglobals
+	lockfile     bool   //nolint:gochecknoglobals
+	reportFormat string //nolint:gochecknoglobals
+	failOn       string //nolint:gochecknoglobals
+	concurrency  int    //nolint:gochecknoglobals
 )
 
 func main() {
@@ -55,12 +132,32 @@ func main() {
 			slog.SetDefault(logger)
 
 			filePath := args[0]
-			runUpdateChecker(filePath, updateType, fix)
+			opts := runOptions{
+				updateType:   updateType,
+				prerelease:   prerelease,
+				fix:          fix,
+				dryRun:       dryRun,
+				lockfile:     lockfile,
+				reportFormat: reportFormat,
+				failOn:       failOn,
+				concurrency:  concurrency,
+			}
+
+			if runUpdateChecker(filePath, opts) {
+				os.Exit(1)
+			}
 		},
 	}
 
 	rootCmd.Flags().StringVarP(&updateType, "update-type", "u", "patch", "Update type: major, minor, patch")
+	rootCmd.Flags().StringVar(&prerelease, "prerelease", PrereleaseNone,
+		"Prerelease handling: none, same-track, any")
 	rootCmd.Flags().BoolVarP(&fix, "fix", "f", false, "Apply updates to the .csproj file")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print a unified diff of the changes --fix would make, without writing them")
+	rootCmd.Flags().BoolVar(&lockfile, "lockfile", false, "Also update packages.lock.json entries when --fix is used")
+	rootCmd.Flags().StringVar(&reportFormat, "report", ReportNone, "Emit an upgrade-class report: none, json, markdown")
+	rootCmd.Flags().StringVar(&failOn, "fail-on", "none", "Exit non-zero when this upgrade class is present: none, major")
+	rootCmd.Flags().IntVar(&concurrency, "concurrency", defaultConcurrency, "Number of packages to check concurrently")
 
 	if err := rootCmd.Execute(); err != nil {
 		slog.Error("Command execution failed", slog.Any("error", err))
@@ -68,13 +165,28 @@ func main() {
 	}
 }
 
-// runUpdateChecker runs the update checker
-func runUpdateChecker(filePath string, mmp string, fix bool) {
+// runOptions bundles the CLI flags runUpdateChecker needs. It replaced a
+// positional parameter list once --report and --fail-on made that list hard
+// to read at the call site.
+type runOptions struct {
+	updateType   string
+	prerelease   string
+	fix          bool
+	dryRun       bool
+	lockfile     bool
+	reportFormat string
+	failOn       string
+	concurrency  int
+}
+
+// runUpdateChecker runs the update checker. It returns true when --fail-on's
+// class was found, telling main to exit non-zero.
+func runUpdateChecker(filePath string, opts runOptions) bool {
 	file, err := os.Open(filePath)
 	if err != nil {
 		slog.Error("Error opening file", slog.Any("error", err))
 
-		return
+		return false
 	}
 	defer file.Close()
 
@@ -82,81 +194,260 @@ func runUpdateChecker(filePath string, mmp string, fix bool) {
 	if err != nil {
 		slog.Error("Error reading file", slog.Any("error", err))
 
-		return
+		return false
 	}
 
 	packages, err := parsePackages(filePath, bytes)
 	if err != nil {
 		slog.Error("Error parsing packages", slog.Any("error", err))
 
-		return
+		return false
+	}
+
+	cfg, err := nuget.Load(filepath.Dir(filePath))
+	if err != nil {
+		slog.Error("Error loading NuGet config", slog.Any("error", err))
+
+		return false
 	}
 
-	updates := checkForUpdates(packages, mmp)
+	ctx := context.Background()
+	client := nuget.NewClient(cfg)
+	updates := checkForUpdates(ctx, client, packages, opts.updateType, opts.prerelease, opts.concurrency)
 
-	if len(updates) > 0 {
-		if fix {
-			if err := updateCsprojFile(filePath, bytes, updates); err != nil {
-				slog.Error("Error updating .csproj file", slog.Any("error", err))
+	if len(updates) == 0 {
+		slog.Info("No updates found")
 
-				return
-			}
+		return false
+	}
+
+	if opts.fix || opts.dryRun {
+		diff, err := updateCsprojFile(filePath, bytes, updates, opts.dryRun, opts.lockfile)
+		if err != nil {
+			slog.Error("Error updating .csproj file", slog.Any("error", err))
+
+			return false
 		}
-		writeUpdates(updates, os.Stdout)
-	} else {
-		slog.Info("No updates found")
+
+		if opts.dryRun && diff != "" {
+			fmt.Fprint(os.Stdout, diff)
+		}
+	}
+
+	writeUpdates(updates, os.Stdout)
+
+	if opts.reportFormat == ReportNone && opts.failOn == "none" {
+		return false
 	}
+
+	rpt := report.Build(ctx, client, toReportUpdates(updates), opts.concurrency)
+
+	if err := writeReport(rpt, opts.reportFormat, os.Stdout); err != nil {
+		slog.Error("Error writing report", slog.Any("error", err))
+	}
+
+	return opts.failOn == "major" && rpt.HasClass(report.ClassMajor)
 }
 
-// parsePackages parses the packages from the given file
-func parsePackages(filePath string, data []byte) ([]Package, error) {
-	if strings.HasSuffix(filePath, ".csproj") {
-		var project Project
-		if err := xml.Unmarshal(data, &project); err != nil {
-			return nil, fmt.Errorf("error parsing .csproj XML: %w", err)
+// toReportUpdates adapts PackageUpdate to report.Update.
+func toReportUpdates(updates []PackageUpdate) []report.Update {
+	out := make([]report.Update, len(updates))
+	for i, update := range updates {
+		out[i] = report.Update{Include: update.Include, CurrentVersion: update.CurrentVersion, NewVersion: update.NewVersion}
+	}
+
+	return out
+}
+
+// writeReport prints rpt in the requested format. ReportNone is a no-op.
+func writeReport(rpt *report.Report, format string, w io.Writer) error {
+	switch format {
+	case ReportJSON:
+		out, err := rpt.JSON()
+		if err != nil {
+			return err
 		}
-		var packages []Package
-		for _, itemGroup := range project.ItemGroup {
-			packages = append(packages, itemGroup.PackageReference...)
+
+		_, err = w.Write(out)
+
+		return err
+	case ReportMarkdown:
+		_, err := io.WriteString(w, rpt.Markdown())
+
+		return err
+	default:
+		return nil
+	}
+}
+
+// parsePackages parses the packages from the given file. When the .csproj is
+// using Central Package Management, versions missing from the PackageReference
+// elements are merged in from the Directory.Packages.props file that owns them.
+func parsePackages(filePath string, data []byte) ([]Package, error) {
+	if !strings.HasSuffix(filePath, ".csproj") {
+		return nil, errors.New("unsupported file type") //nolint:goerr113
+	}
+
+	var project Project
+	if err := xml.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("error parsing .csproj XML: %w", err)
+	}
+
+	var packages []Package
+	for _, itemGroup := range project.ItemGroup {
+		packages = append(packages, itemGroup.PackageReference...)
+	}
+
+	cpmVersions, err := loadCentralPackageVersions(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range packages {
+		packages[i].Name = packageName(packages[i])
+		packages[i].VersionSource = VersionSourceCsproj
+
+		if packages[i].Version == "" {
+			if version, ok := cpmVersions[packages[i].Name]; ok {
+				packages[i].Version = version
+				packages[i].VersionSource = VersionSourceCPM
+			}
 		}
 
-		return packages, nil
+		packages[i].Spec = parseVersionSpec(packages[i].Version)
 	}
 
-	return nil, errors.New("unsupported file type") //nolint:goerr113
+	return packages, nil
 }
 
-// checkForUpdates checks for updates for the given packages
-func checkForUpdates(packages []Package, mmp string) []PackageUpdate {
+// packageName returns the package id for a PackageReference or PackageVersion
+// element, preferring Include and falling back to Update.
+func packageName(pkg Package) string {
+	if pkg.Include != "" {
+		return pkg.Include
+	}
+
+	return pkg.Update
+}
+
+// loadCentralPackageVersions looks for a Directory.Packages.props file
+// starting in the directory that contains csprojPath and walking up to the
+// filesystem root, and returns the package-id -> version map it declares. It
+// returns a nil map when the project isn't using Central Package Management.
+func loadCentralPackageVersions(csprojPath string) (map[string]string, error) {
+	propsPath, err := findCentralPackageProps(csprojPath)
+	if err != nil || propsPath == "" {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(propsPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", centralPackagePropsFile, err)
+	}
+
+	var props PackagesProps
+	if err := xml.Unmarshal(data, &props); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", centralPackagePropsFile, err)
+	}
+
+	versions := make(map[string]string)
+	for _, itemGroup := range props.ItemGroup {
+		for _, pv := range itemGroup.PackageVersion {
+			versions[packageName(pv)] = pv.Version
+		}
+	}
+
+	return versions, nil
+}
+
+// findCentralPackageProps walks up from the directory containing csprojPath
+// looking for a Directory.Packages.props file, matching how MSBuild resolves
+// it relative to the importing project.
+func findCentralPackageProps(csprojPath string) (string, error) {
+	dir := filepath.Dir(csprojPath)
+	for {
+		candidate := filepath.Join(dir, centralPackagePropsFile)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		} else if !os.IsNotExist(err) {
+			return "", fmt.Errorf("error checking for %s: %w", centralPackagePropsFile, err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// checkForUpdates checks for updates for the given packages, fetching up to
+// concurrency registration indexes at once. Results are returned in the same
+// order as packages regardless of which goroutine finished first.
+func checkForUpdates(
+	ctx context.Context, client *nuget.Client, packages []Package, mmp string, prerelease string, concurrency int,
+) []PackageUpdate {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	found := make([]*PackageUpdate, len(packages))
+
+	var wg sync.WaitGroup
+
+	jobs := make(chan int)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for idx := range jobs {
+				found[idx] = checkPackageForUpdate(ctx, client, packages[idx], mmp, prerelease)
+			}
+		}()
+	}
+
+	for idx := range packages {
+		jobs <- idx
+	}
+	close(jobs)
+
+	wg.Wait()
+
 	var updates []PackageUpdate
-	for _, pkg := range packages {
-		latestVersion := getLatestVersion(pkg, mmp)
-		if latestVersion != "" && latestVersion != pkg.Version {
-			updates = append(updates, PackageUpdate{
-				Include:        pkg.Include,
-				CurrentVersion: pkg.Version,
-				NewVersion:     latestVersion,
-			})
+
+	for _, update := range found {
+		if update != nil {
+			updates = append(updates, *update)
 		}
 	}
 
 	return updates
 }
 
-// getLatestVersion gets the latest version of the given package
-func getLatestVersion(pkg Package, mmp string) string {
-	url := fmt.Sprintf("https://api.nuget.org/v3/registration5-gz-semver1/%s/index.json", strings.ToLower(pkg.Include))
-	resp, err := http.Get(url) //nolint:gosec, noctx
-	if err != nil {
-		slog.Error("Error fetching package info", slog.Any("error", err))
+// checkPackageForUpdate checks a single package for an update, returning nil
+// when none applies.
+func checkPackageForUpdate(ctx context.Context, client *nuget.Client, pkg Package, mmp string, prerelease string) *PackageUpdate {
+	latestVersion := getLatestVersion(ctx, client, pkg, mmp, prerelease)
+	if latestVersion == "" || latestVersion == pkg.Version {
+		return nil
+	}
 
-		return ""
+	return &PackageUpdate{
+		Include:        pkg.Name,
+		CurrentVersion: pkg.Version,
+		NewVersion:     latestVersion,
+		VersionSource:  pkg.VersionSource,
 	}
-	defer resp.Body.Close()
+}
 
-	body, err := io.ReadAll(resp.Body)
+// getLatestVersion gets the latest version of the given package
+func getLatestVersion(ctx context.Context, client *nuget.Client, pkg Package, mmp string, prerelease string) string {
+	body, err := client.FetchRegistrationIndex(ctx, pkg.Name)
 	if err != nil {
-		slog.Error("Error reading response", slog.Any("error", err))
+		slog.Error("Error fetching package info", slog.Any("error", err))
 
 		return ""
 	}
@@ -166,22 +457,52 @@ func getLatestVersion(pkg Package, mmp string) string {
 		return ""
 	}
 
-	currentVersion, err := semver.NewVersion(pkg.Version)
+	// Floating versions and ranges don't resolve to a single concrete
+	// version, so comparisons are anchored to the spec's floor instead. A
+	// missing floor (an Update="" element with no Version and no matching
+	// CPM entry) has nothing to compare against, so skip it rather than
+	// fabricating one — proposing an update nugbot can't write back to any
+	// Version attribute would report a fix that never happened.
+	if pkg.Spec.Floor == "" {
+		return ""
+	}
+
+	currentVersion, err := semver.NewVersion(pkg.Spec.Floor)
 	if err != nil {
 		slog.Error("Error parsing current version", slog.Any("error", err))
 
 		return ""
 	}
 
-	return findLatestVersion(versions.Array(), currentVersion, mmp)
+	var ceiling *semver.Version
+
+	if pkg.Spec.Ceiling != "" {
+		ceiling, err = semver.NewVersion(pkg.Spec.Ceiling)
+		if err != nil {
+			slog.Error("Error parsing ceiling version", slog.Any("error", err))
+
+			return ""
+		}
+	}
+
+	return findLatestVersion(versions.Array(), currentVersion, mmp, prerelease, ceiling, pkg.Spec.CeilingInclusive)
 }
 
-// findLatestVersion finds the latest version from the given versions
-func findLatestVersion(versions []gjson.Result, currentVersion *semver.Version, mmp string) string {
+// findLatestVersion finds the latest version from the given versions that
+// doesn't exceed ceiling (a range spec's upper bound, nil if unbounded),
+// regardless of how permissive mmp is.
+func findLatestVersion(
+	versions []gjson.Result, currentVersion *semver.Version, mmp string, prerelease string,
+	ceiling *semver.Version, ceilingInclusive bool,
+) string {
 	var latestVersion *semver.Version
 	for _, version := range flattenVersions(versions) {
 		ver, err := semver.NewVersion(version.String())
-		if err != nil || ver.Prerelease() != "" {
+		if err != nil || !prereleaseAllowed(currentVersion, ver, prerelease) {
+			continue
+		}
+
+		if !withinCeiling(ver, ceiling, ceilingInclusive) {
 			continue
 		}
 
@@ -198,6 +519,46 @@ func findLatestVersion(versions []gjson.Result, currentVersion *semver.Version,
 	return ""
 }
 
+// withinCeiling reports whether ver satisfies a range's upper bound, so e.g.
+// "[1.0,2.0)" never proposes a version past 2.0 even when --update-type=major
+// would otherwise allow it.
+func withinCeiling(ver, ceiling *semver.Version, inclusive bool) bool {
+	if ceiling == nil {
+		return true
+	}
+
+	if inclusive {
+		return !ver.GreaterThan(ceiling)
+	}
+
+	return ver.LessThan(ceiling)
+}
+
+// prereleaseAllowed reports whether ver may be proposed as an update given the
+// --prerelease mode, mirroring the "avoid accidental downgrades" spirit of
+// `go get -u`: a prerelease must never silently outrank a stable release, and
+// is only ever considered when the caller has opted in.
+func prereleaseAllowed(currentVersion, ver *semver.Version, prerelease string) bool {
+	if ver.Prerelease() == "" {
+		return true
+	}
+
+	switch prerelease {
+	case PrereleaseAny:
+		return true
+	case PrereleaseSameTrack:
+		return currentVersion.Prerelease() != "" && samePrereleaseBase(currentVersion, ver)
+	default: // PrereleaseNone
+		return false
+	}
+}
+
+// samePrereleaseBase reports whether a and b share the same major.minor.patch,
+// i.e. b is a candidate for "a later prerelease of the same release".
+func samePrereleaseBase(a, b *semver.Version) bool {
+	return a.Major() == b.Major() && a.Minor() == b.Minor() && a.Patch() == b.Patch()
+}
+
 // flattenVersions flattens the versions array
 func flattenVersions(versions []gjson.Result) []gjson.Result {
 	var flatVersions []gjson.Result
@@ -222,47 +583,97 @@ func isValidUpdate(currentVersion, ver, latestVersion *semver.Version, mmp strin
 	return false
 }
 
-// updateCsprojFile updates the .csproj file with the new versions
-func updateCsprojFile(_ string, _ []byte, _ []PackageUpdate) error {
-	return errors.New("not implemented") //nolint:goerr113
-	// // Load the original XML
-	// var project Project
-	//
-	//	if err := xml.Unmarshal(data, &project); err != nil {
-	//		return fmt.Errorf("error parsing .csproj XML: %w", err)
-	//	}
-	//
-	// // Create a map of updates for easy lookup
-	// updateMap := make(map[string]string)
-	//
-	//	for _, update := range updates {
-	//		updateMap[update.Include] = update.NewVersion
-	//	}
-	//
-	// // Update the versions in the project structure
-	//
-	//	for i := range project.ItemGroup {
-	//		for j := range project.ItemGroup[i].PackageReference {
-	//			if newVersion, exists := updateMap[project.ItemGroup[i].PackageReference[j].Include]; exists {
-	//				project.ItemGroup[i].PackageReference[j].Version = newVersion
-	//			}
-	//		}
-	//	}
-	//
-	// // Marshal the updated project back to XML
-	// output, err := xml.MarshalIndent(project, "", "  ")
-	//
-	//	if err != nil {
-	//		return fmt.Errorf("error marshalling .csproj XML: %w", err)
-	//	}
-	//
-	// // Write the updated XML back to the file
-	//
-	//	if err := os.WriteFile(filePath, output, 0644); err != nil {
-	//		return fmt.Errorf("error writing .csproj file: %w", err)
-	//	}
-	//
-	// return nil
+// updateCsprojFile applies updates to the .csproj file and, for any package
+// sourced from Central Package Management, to the Directory.Packages.props
+// that actually owns its version. It never round-trips through encoding/xml's
+// marshaller (see rewriteElementVersions); in dryRun mode nothing is written
+// and a unified diff of the would-be changes is returned instead. When
+// lockfile is set, matching entries in an adjacent packages.lock.json are
+// updated too.
+func updateCsprojFile(filePath string, data []byte, updates []PackageUpdate, dryRun, lockfile bool) (string, error) {
+	csprojUpdates := make(map[string]string)
+	cpmUpdates := make(map[string]string)
+
+	for _, update := range updates {
+		if update.VersionSource == VersionSourceCPM {
+			cpmUpdates[update.Include] = update.NewVersion
+		} else {
+			csprojUpdates[update.Include] = update.NewVersion
+		}
+	}
+
+	var diffs strings.Builder
+
+	if len(csprojUpdates) > 0 {
+		updated, err := rewriteElementVersions(data, "PackageReference", csprojUpdates)
+		if err != nil {
+			return "", fmt.Errorf("error updating %s: %w", filePath, err)
+		}
+
+		if err := writeOrDiff(filePath, data, updated, dryRun, &diffs); err != nil {
+			return "", err
+		}
+	}
+
+	if len(cpmUpdates) > 0 {
+		if err := updateCentralPackageVersions(filePath, cpmUpdates, dryRun, &diffs); err != nil {
+			return "", err
+		}
+	}
+
+	if lockfile {
+		if err := updatePackagesLockFile(filePath, updates, dryRun, &diffs); err != nil {
+			return "", err
+		}
+	}
+
+	return diffs.String(), nil
+}
+
+// updateCentralPackageVersions rewrites the PackageVersion entries of the
+// Directory.Packages.props belonging to csprojPath.
+func updateCentralPackageVersions(csprojPath string, updates map[string]string, dryRun bool, diffs *strings.Builder) error {
+	propsPath, err := findCentralPackageProps(csprojPath)
+	if err != nil {
+		return err
+	}
+
+	if propsPath == "" {
+		return fmt.Errorf("no %s found for %s", centralPackagePropsFile, csprojPath) //nolint:goerr113
+	}
+
+	data, err := os.ReadFile(propsPath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", propsPath, err)
+	}
+
+	updated, err := rewriteElementVersions(data, "PackageVersion", updates)
+	if err != nil {
+		return fmt.Errorf("error updating %s: %w", propsPath, err)
+	}
+
+	return writeOrDiff(propsPath, data, updated, dryRun, diffs)
+}
+
+// writeOrDiff writes after to path, unless dryRun is set, in which case a
+// unified diff against before is appended to diffs instead. A no-op edit
+// (after == before) is silently skipped either way.
+func writeOrDiff(path string, before, after []byte, dryRun bool, diffs *strings.Builder) error {
+	if bytes.Equal(before, after) {
+		return nil
+	}
+
+	if dryRun {
+		diffs.WriteString(unifiedDiff(path, before, after))
+
+		return nil
+	}
+
+	if err := os.WriteFile(path, after, 0o644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	return nil
 }
 
 // writeUpdates writes the updates to stdout
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile(%s): %v", path, err)
+	}
+}
+
+func TestParsePackagesMergesCentralPackageVersions(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, centralPackagePropsFile), `<Project>
+  <ItemGroup>
+    <PackageVersion Include="Newtonsoft.Json" Version="12.0.1" />
+  </ItemGroup>
+</Project>
+`)
+
+	csprojPath := filepath.Join(dir, "app.csproj")
+	data := []byte(`<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" />
+    <PackageReference Include="Serilog" Version="2.10.0" />
+  </ItemGroup>
+</Project>
+`)
+
+	packages, err := parsePackages(csprojPath, data)
+	if err != nil {
+		t.Fatalf("parsePackages: %v", err)
+	}
+
+	byName := make(map[string]Package, len(packages))
+	for _, pkg := range packages {
+		byName[pkg.Name] = pkg
+	}
+
+	cpm, ok := byName["Newtonsoft.Json"]
+	if !ok {
+		t.Fatalf("packages = %+v, want a Newtonsoft.Json entry", packages)
+	}
+
+	if cpm.Version != "12.0.1" || cpm.VersionSource != VersionSourceCPM {
+		t.Errorf("Newtonsoft.Json = %+v, want Version 12.0.1 sourced from CPM", cpm)
+	}
+
+	if cpm.Spec.Floor != "12.0.1" {
+		t.Errorf("Newtonsoft.Json.Spec.Floor = %q, want 12.0.1 (the merged-in CPM version parsed too)", cpm.Spec.Floor)
+	}
+
+	csproj, ok := byName["Serilog"]
+	if !ok {
+		t.Fatalf("packages = %+v, want a Serilog entry", packages)
+	}
+
+	if csproj.Version != "2.10.0" || csproj.VersionSource != VersionSourceCsproj {
+		t.Errorf("Serilog = %+v, want its own Version, sourced from the .csproj", csproj)
+	}
+}
+
+func TestParsePackagesWithoutCentralPackagePropsLeavesMissingVersionsEmpty(t *testing.T) {
+	dir := t.TempDir()
+	csprojPath := filepath.Join(dir, "app.csproj")
+	data := []byte(`<Project>
+  <ItemGroup>
+    <PackageReference Update="Foo" />
+  </ItemGroup>
+</Project>
+`)
+
+	packages, err := parsePackages(csprojPath, data)
+	if err != nil {
+		t.Fatalf("parsePackages: %v", err)
+	}
+
+	if len(packages) != 1 || packages[0].Version != "" || packages[0].Spec.Floor != "" {
+		t.Errorf("packages = %+v, want a single unresolved Foo entry with no version or floor", packages)
+	}
+}
+
+func TestUpdateCsprojFileRoutesUpdateToTheFileThatOwnsTheVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	propsPath := filepath.Join(dir, centralPackagePropsFile)
+	writeFile(t, propsPath, `<Project>
+  <ItemGroup>
+    <PackageVersion Include="Cpm.Pkg" Version="1.0.0" />
+  </ItemGroup>
+</Project>
+`)
+
+	csprojPath := filepath.Join(dir, "app.csproj")
+	csprojData := []byte(`<Project>
+  <ItemGroup>
+    <PackageReference Include="Cpm.Pkg" />
+    <PackageReference Include="Direct.Pkg" Version="1.0.0" />
+  </ItemGroup>
+</Project>
+`)
+	writeFile(t, csprojPath, string(csprojData))
+
+	updates := []PackageUpdate{
+		{Include: "Cpm.Pkg", CurrentVersion: "1.0.0", NewVersion: "1.1.0", VersionSource: VersionSourceCPM},
+		{Include: "Direct.Pkg", CurrentVersion: "1.0.0", NewVersion: "1.2.0", VersionSource: VersionSourceCsproj},
+	}
+
+	if _, err := updateCsprojFile(csprojPath, csprojData, updates, false, false); err != nil {
+		t.Fatalf("updateCsprojFile: %v", err)
+	}
+
+	gotCsproj, err := os.ReadFile(csprojPath)
+	if err != nil {
+		t.Fatalf("ReadFile(csproj): %v", err)
+	}
+
+	if !strings.Contains(string(gotCsproj), `Include="Direct.Pkg" Version="1.2.0"`) {
+		t.Errorf(".csproj not rewritten for the .csproj-owned package, got:\n%s", gotCsproj)
+	}
+
+	if strings.Contains(string(gotCsproj), `Version="1.1.0"`) {
+		t.Errorf(".csproj should not gain a Version attribute for a CPM-owned package, got:\n%s", gotCsproj)
+	}
+
+	gotProps, err := os.ReadFile(propsPath)
+	if err != nil {
+		t.Fatalf("ReadFile(props): %v", err)
+	}
+
+	if !strings.Contains(string(gotProps), `Include="Cpm.Pkg" Version="1.1.0"`) {
+		t.Errorf("Directory.Packages.props not rewritten for the CPM-owned package, got:\n%s", gotProps)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestPrereleaseAllowed(t *testing.T) {
+	stable := mustVersion(t, "1.0.0")
+	samePrerelease := mustVersion(t, "1.0.0-beta.1")
+	otherSamePrerelease := mustVersion(t, "1.0.0-beta.2")
+	laterStable := mustVersion(t, "1.1.0")
+	laterPrerelease := mustVersion(t, "1.1.0-beta.1")
+
+	tests := []struct {
+		name    string
+		current *semver.Version
+		ver     *semver.Version
+		mode    string
+		want    bool
+	}{
+		{"stable candidate always allowed", stable, laterStable, PrereleaseNone, true},
+		{"prerelease rejected under none", stable, laterPrerelease, PrereleaseNone, false},
+		{"prerelease allowed under any", stable, laterPrerelease, PrereleaseAny, true},
+		{"same-track rejects when current isn't a prerelease", stable, otherSamePrerelease, PrereleaseSameTrack, false},
+		{"same-track allows a later prerelease of the same release", samePrerelease, otherSamePrerelease, PrereleaseSameTrack, true},
+		{"same-track rejects a prerelease of a different release", samePrerelease, laterPrerelease, PrereleaseSameTrack, false},
+	}
+
+	for _, test := range tests {
+		if got := prereleaseAllowed(test.current, test.ver, test.mode); got != test.want {
+			t.Errorf("%s: prereleaseAllowed(%s, %s, %s) = %v, want %v",
+				test.name, test.current, test.ver, test.mode, got, test.want)
+		}
+	}
+}
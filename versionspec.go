@@ -0,0 +1,97 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// VersionSpecKind identifies the syntactic form of a PackageReference's Version
+// attribute.
+type VersionSpecKind int
+
+const (
+	// VersionSpecExact is a plain pinned version such as "1.2.3".
+	VersionSpecExact VersionSpecKind = iota
+	// VersionSpecFloating is a wildcard version such as "1.2.*" or "*".
+	VersionSpecFloating
+	// VersionSpecRange is an interval range such as "[1.0,2.0)".
+	VersionSpecRange
+)
+
+// VersionSpec is a parsed NuGet version requirement, covering the three forms
+// nuget.exe understands: an exact version ("1.2.3"), a floating version
+// ("1.2.*", "1.*", "*"), and an interval range ("[1.0,2.0)", "(1.0,]").
+type VersionSpec struct {
+	Kind VersionSpecKind
+	Raw  string
+
+	// Floor is the lower bound implied by the spec: the fixed prefix of a
+	// floating version, or the lower bound of a range. It's what we anchor
+	// "current version" comparisons to, since neither form resolves to a
+	// single concrete version on its own.
+	Floor string
+	// FloorInclusive reports whether Floor itself satisfies the spec (always
+	// true for floating versions; depends on the bracket used for ranges).
+	FloorInclusive bool
+
+	// Ceiling is the upper bound of a range, e.g. "2.0" in "[1.0,2.0)"; empty
+	// for an unbounded range ("[1.0,)") or any non-range spec. findLatestVersion
+	// never proposes a version past it, regardless of --update-type.
+	Ceiling string
+	// CeilingInclusive reports whether Ceiling itself satisfies the spec
+	// (the bracket used on the upper bound).
+	CeilingInclusive bool
+}
+
+var floatingVersionPattern = regexp.MustCompile(`^\d+(\.\d+)*\.\*$|^\*$`) //nolint:gochecknoglobals
+
+// parseVersionSpec classifies a raw Version attribute value into the spec kind
+// NuGet would use to resolve it.
+func parseVersionSpec(raw string) VersionSpec {
+	raw = strings.TrimSpace(raw)
+
+	switch {
+	case strings.HasPrefix(raw, "[") || strings.HasPrefix(raw, "("):
+		return parseVersionRange(raw)
+	case floatingVersionPattern.MatchString(raw):
+		return VersionSpec{Kind: VersionSpecFloating, Raw: raw, Floor: floatingFloor(raw), FloorInclusive: true}
+	default:
+		return VersionSpec{Kind: VersionSpecExact, Raw: raw, Floor: raw, FloorInclusive: true}
+	}
+}
+
+// floatingFloor returns the fixed portion of a floating version, e.g.
+// "1.2.*" -> "1.2", "*" -> "".
+func floatingFloor(raw string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(raw, "*"), ".")
+}
+
+// parseVersionRange parses a NuGet interval range such as "[1.0,2.0)" or
+// "(1.0,]" into a VersionSpec, tracking both bounds so findLatestVersion can
+// avoid proposing an out-of-range update. A bracket form with no comma, such
+// as "[1.0]", pins an exact version: floor and ceiling coincide.
+func parseVersionRange(raw string) VersionSpec {
+	floorInclusive := strings.HasPrefix(raw, "[")
+	ceilingInclusive := strings.HasSuffix(raw, "]")
+	inner := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(raw, "["), "("), ")")
+	inner = strings.TrimSuffix(inner, "]")
+
+	parts := strings.SplitN(inner, ",", 2)
+	floor := strings.TrimSpace(parts[0])
+
+	if len(parts) == 1 {
+		return VersionSpec{
+			Kind: VersionSpecRange, Raw: raw,
+			Floor: floor, FloorInclusive: true,
+			Ceiling: floor, CeilingInclusive: true,
+		}
+	}
+
+	ceiling := strings.TrimSpace(parts[1])
+
+	return VersionSpec{
+		Kind: VersionSpecRange, Raw: raw,
+		Floor: floor, FloorInclusive: floorInclusive,
+		Ceiling: ceiling, CeilingInclusive: ceilingInclusive,
+	}
+}
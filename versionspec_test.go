@@ -0,0 +1,125 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func mustVersion(t *testing.T, raw string) *semver.Version {
+	t.Helper()
+
+	v, err := semver.NewVersion(raw)
+	if err != nil {
+		t.Fatalf("semver.NewVersion(%q): %v", raw, err)
+	}
+
+	return v
+}
+
+func TestParseVersionSpecExact(t *testing.T) {
+	spec := parseVersionSpec("1.2.3")
+
+	if spec.Kind != VersionSpecExact {
+		t.Fatalf("Kind = %v, want VersionSpecExact", spec.Kind)
+	}
+
+	if spec.Floor != "1.2.3" || !spec.FloorInclusive {
+		t.Errorf("Floor = %q, FloorInclusive = %v", spec.Floor, spec.FloorInclusive)
+	}
+
+	if spec.Ceiling != "" {
+		t.Errorf("Ceiling = %q, want empty for an exact version", spec.Ceiling)
+	}
+}
+
+func TestParseVersionSpecFloating(t *testing.T) {
+	tests := []struct {
+		raw       string
+		wantFloor string
+	}{
+		{"1.2.*", "1.2"},
+		{"1.*", "1"},
+		{"*", ""},
+	}
+
+	for _, test := range tests {
+		spec := parseVersionSpec(test.raw)
+
+		if spec.Kind != VersionSpecFloating {
+			t.Errorf("parseVersionSpec(%q).Kind = %v, want VersionSpecFloating", test.raw, spec.Kind)
+		}
+
+		if spec.Floor != test.wantFloor {
+			t.Errorf("parseVersionSpec(%q).Floor = %q, want %q", test.raw, spec.Floor, test.wantFloor)
+		}
+
+		if !spec.FloorInclusive {
+			t.Errorf("parseVersionSpec(%q).FloorInclusive = false, want true", test.raw)
+		}
+	}
+}
+
+func TestParseVersionSpecRange(t *testing.T) {
+	tests := []struct {
+		raw                  string
+		wantFloor            string
+		wantFloorInclusive   bool
+		wantCeiling          string
+		wantCeilingInclusive bool
+	}{
+		{"[1.0,2.0)", "1.0", true, "2.0", false},
+		{"(1.0,2.0]", "1.0", false, "2.0", true},
+		{"[1.0,)", "1.0", true, "", false},
+		{"(1.0,]", "1.0", false, "", true},
+		{"[1.0]", "1.0", true, "1.0", true},
+	}
+
+	for _, test := range tests {
+		spec := parseVersionSpec(test.raw)
+
+		if spec.Kind != VersionSpecRange {
+			t.Fatalf("parseVersionSpec(%q).Kind = %v, want VersionSpecRange", test.raw, spec.Kind)
+		}
+
+		if spec.Floor != test.wantFloor || spec.FloorInclusive != test.wantFloorInclusive {
+			t.Errorf("parseVersionSpec(%q) floor = (%q, %v), want (%q, %v)",
+				test.raw, spec.Floor, spec.FloorInclusive, test.wantFloor, test.wantFloorInclusive)
+		}
+
+		if spec.Ceiling != test.wantCeiling || spec.CeilingInclusive != test.wantCeilingInclusive {
+			t.Errorf("parseVersionSpec(%q) ceiling = (%q, %v), want (%q, %v)",
+				test.raw, spec.Ceiling, spec.CeilingInclusive, test.wantCeiling, test.wantCeilingInclusive)
+		}
+	}
+}
+
+func TestWithinCeilingUnbounded(t *testing.T) {
+	if !withinCeiling(mustVersion(t, "99.0.0"), nil, false) {
+		t.Error("a nil ceiling should never reject a version")
+	}
+}
+
+func TestWithinCeilingExclusive(t *testing.T) {
+	ceiling := mustVersion(t, "2.0.0")
+
+	if withinCeiling(mustVersion(t, "2.0.0"), ceiling, false) {
+		t.Error("2.0.0 should not satisfy an exclusive ceiling of 2.0.0")
+	}
+
+	if !withinCeiling(mustVersion(t, "1.9.9"), ceiling, false) {
+		t.Error("1.9.9 should satisfy an exclusive ceiling of 2.0.0")
+	}
+}
+
+func TestWithinCeilingInclusive(t *testing.T) {
+	ceiling := mustVersion(t, "2.0.0")
+
+	if !withinCeiling(mustVersion(t, "2.0.0"), ceiling, true) {
+		t.Error("2.0.0 should satisfy an inclusive ceiling of 2.0.0")
+	}
+
+	if withinCeiling(mustVersion(t, "2.0.1"), ceiling, true) {
+		t.Error("2.0.1 should not satisfy an inclusive ceiling of 2.0.0")
+	}
+}